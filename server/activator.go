@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActivatorRefreshHandler makes vMix resend current state to activator devices such as control surfaces
+// for [POST] /api/activators/refresh .
+// The function is sent by sendFunction rather than vmixgo.Vmix.ActivatorRefresh, which bypasses
+// function timeouts, error markers and metrics.
+// vMix API XML does not expose configured activators, so they cannot be listed.
+func ActivatorRefreshHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
+	if err := sendFunction(vmix, "ActivatorRefresh", nil); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
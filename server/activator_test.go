@@ -0,0 +1,16 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestActivatorRefreshHandler(t *testing.T) {
+	s, r := setupTest(t)
+	if w := doRequest(r, http.MethodPost, "/api/activators/refresh", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "ActivatorRefresh" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
@@ -0,0 +1,576 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// SetBalance sets audio balance of input. value is clamped to -1 (left) to 1 (right).
+func SetBalance(v *vmixgo.Vmix, input string, value float64) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(clamp(value, -1, 1))
+	return sendFunction(v, "SetBalance", params)
+}
+
+// SetPan sets audio balance of input like a mixer pan knob. value is clamped to -100 (left) to 100 (right).
+func SetPan(v *vmixgo.Vmix, input string, value float64) error {
+	return SetBalance(v, input, clamp(value, -100, 100)/100)
+}
+
+// SetGain sets input gain in dB. value is clamped to 0 to 24.
+func SetGain(v *vmixgo.Vmix, input string, value float64) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(clamp(value, 0, 24))
+	return sendFunction(v, "SetGain", params)
+}
+
+// SetVolume sets input volume. value is clamped to 0 to 100.
+func SetVolume(v *vmixgo.Vmix, input string, value float64) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(clamp(value, 0, 100))
+	return sendFunction(v, "SetVolume", params)
+}
+
+// SetVolumeFade fades input volume to value over duration in milliseconds. value is clamped to 0 to 100.
+func SetVolumeFade(v *vmixgo.Vmix, input string, value float64, duration uint) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(clamp(value, 0, 100)) + "," + strconv.Itoa(int(duration))
+	return sendFunction(v, "SetVolumeFade", params)
+}
+
+// SetBalanceRequest Request JSON for SetBalanceHandler
+type SetBalanceRequest struct {
+	Input   string  `json:"input"`   // input key, number or title.
+	Balance float64 `json:"balance"` // -1 (left) to 1 (right).
+}
+
+// SetBalanceHandler sets input audio balance for [POST] /api/audio/balance and returns current balance.
+func SetBalanceHandler(c *gin.Context) {
+	req := SetBalanceRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := SetBalance(vmix, input.Key, req.Balance); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, _ = findInput(input.Key)
+	c.JSON(http.StatusOK, gin.H{
+		"input":   input.Key,
+		"balance": input.Balance,
+	})
+}
+
+// defaultNormalizeTarget is default target peak level in dBFS.
+const defaultNormalizeTarget = -6
+
+// NormalizeRequest Request JSON for NormalizeHandler
+type NormalizeRequest struct {
+	Input    string   `json:"input"`     // input key, number or title.
+	TargetDb *float64 `json:"target_db"` // target peak in dBFS. defaults to -6.
+	Apply    bool     `json:"apply"`     // apply computed gain or not.
+}
+
+// NormalizeResult is a gain suggestion computed by NormalizeHandler.
+type NormalizeResult struct {
+	Input        string  `json:"input"`         // input key.
+	PeakDb       float64 `json:"peak_db"`       // current peak in dBFS.
+	TargetDb     float64 `json:"target_db"`     // target peak in dBFS.
+	AdjustmentDb float64 `json:"adjustment_db"` // gain change to reach target.
+	GainDb       float64 `json:"gain_db"`       // resulting gain, clamped to vMix range.
+	Clamped      bool    `json:"clamped"`       // gain hit 0 or 24dB and target can not be reached.
+	Applied      bool    `json:"applied"`       // gain was sent to vMix.
+}
+
+// meterToDb converts vMix meter value (0 to 1 amplitude) to dBFS, rounded to 0.1dB.
+func meterToDb(meter float64) float64 {
+	return math.Round(20*math.Log10(meter)*10) / 10
+}
+
+// NormalizeHandler suggests or applies input gain to bring current peak to target for [POST] /api/audio/normalize .
+// Peak is read from meterF1/meterF2 of the latest refresh, so the input should be playing representative audio.
+func NormalizeHandler(c *gin.Context) {
+	req := NormalizeRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	peak := math.Max(input.MeterF1, input.MeterF2)
+	if peak <= 0 {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error": "Input has no audio signal",
+		})
+		return
+	}
+	gain, _ := strconv.ParseFloat(input.GainDb, 64)
+
+	res := NormalizeResult{
+		Input:    input.Key,
+		PeakDb:   meterToDb(peak),
+		TargetDb: defaultNormalizeTarget,
+	}
+	if req.TargetDb != nil {
+		res.TargetDb = *req.TargetDb
+	}
+	res.AdjustmentDb = res.TargetDb - res.PeakDb
+	res.GainDb = clamp(gain+res.AdjustmentDb, 0, 24)
+	res.Clamped = res.GainDb != gain+res.AdjustmentDb
+
+	vmix, _ := vmixSnapshot()
+	if req.Apply {
+		if err := SetGain(vmix, input.Key, res.GainDb); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		res.Applied = true
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// busFunctionValue returns Value of AudioBusOn/AudioBusOff for bus name. "master" is "M" .
+func busFunctionValue(bus string) string {
+	if bus == masterMeterKey {
+		return "M"
+	}
+	return bus
+}
+
+// inputBuses parses AudioBusses attribute such as "M,A" into bus names such as ["master", "A"] .
+func inputBuses(audioBusses string) map[string]bool {
+	buses := map[string]bool{}
+	for _, b := range strings.Split(strings.ReplaceAll(audioBusses, ",", ""), "") {
+		if b == "M" {
+			b = masterMeterKey
+		}
+		if b != "" {
+			buses[b] = true
+		}
+	}
+	return buses
+}
+
+// AudioBusOn assigns input to bus. bus is "master" or "A" to "G" .
+func AudioBusOn(v *vmixgo.Vmix, input string, bus string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = busFunctionValue(bus)
+	return sendFunction(v, "AudioBusOn", params)
+}
+
+// AudioBusOff removes input from bus. bus is "master" or "A" to "G" .
+func AudioBusOff(v *vmixgo.Vmix, input string, bus string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = busFunctionValue(bus)
+	return sendFunction(v, "AudioBusOff", params)
+}
+
+// AudioMatrixRow is bus assignments of an input.
+type AudioMatrixRow struct {
+	Input  string          `json:"input"`  // input key.
+	Number uint            `json:"number"` // input number.
+	Title  string          `json:"title"`  // input title.
+	Buses  map[string]bool `json:"buses"`  // bus name to assigned or not, for every bus in "buses".
+}
+
+// audioMatrix returns buses available in vMix and bus assignments of every input.
+func audioMatrix(v *vmixgo.Vmix, e *vMixExtra) (buses []string, rows []AudioMatrixRow) {
+	for _, b := range e.Audio.Bus {
+		buses = append(buses, b.Name())
+	}
+	rows = make([]AudioMatrixRow, 0, len(v.Inputs.Input))
+	for _, input := range v.Inputs.Input {
+		assigned := inputBuses(input.AudioBusses)
+		row := AudioMatrixRow{Input: input.Key, Number: input.Number, Title: input.Title, Buses: map[string]bool{}}
+		for _, b := range buses {
+			row.Buses[b] = assigned[b]
+		}
+		rows = append(rows, row)
+	}
+	return buses, rows
+}
+
+// GetAudioMatrixHandler returns input x bus assignments for [GET] /api/audio/matrix .
+func GetAudioMatrixHandler(c *gin.Context) {
+	vmix, vmixExtra := vmixSnapshot()
+	buses, rows := audioMatrix(vmix, vmixExtra)
+	c.JSON(http.StatusOK, gin.H{
+		"buses":  buses,
+		"inputs": rows,
+	})
+}
+
+// SetAudioMatrixRequest Request JSON for SetAudioMatrixHandler
+type SetAudioMatrixRequest struct {
+	Input string `json:"input"` // input key, number or title.
+	Bus   string `json:"bus"`   // "master" or "A" to "G" .
+	On    bool   `json:"on"`    // assign or remove.
+}
+
+// SetAudioMatrixHandler assigns input to bus or removes it for [POST] /api/audio/matrix ,
+// and returns updated matrix.
+func SetAudioMatrixHandler(c *gin.Context) {
+	req := SetAudioMatrixRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	valid := false
+	for _, b := range audioBuses {
+		valid = valid || b == req.Bus
+	}
+	if !valid {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unknown bus %q. valid buses: %s", req.Bus, strings.Join(audioBuses, ", ")),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	send := AudioBusOff
+	if req.On {
+		send = AudioBusOn
+	}
+	vmix, vmixExtra := vmixSnapshot()
+	if err := send(vmix, input.Key, req.Bus); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, vmixExtra = vmixSnapshot()
+	buses, rows := audioMatrix(vmix, vmixExtra)
+	c.JSON(http.StatusOK, gin.H{
+		"buses":  buses,
+		"inputs": rows,
+	})
+}
+
+// AdjustVolumeRequest Request JSON for AdjustVolumeHandler
+type AdjustVolumeRequest struct {
+	Input string  `json:"input"` // input key, number or title.
+	Delta float64 `json:"delta"` // volume change. e.g. 5 or -5 .
+}
+
+// AdjustVolumeHandler changes input volume by delta for [POST] /api/audio/volume/adjust and returns new volume.
+// Current volume is read from fresh XML right before, so repeated bumps do not drift.
+func AdjustVolumeHandler(c *gin.Context) {
+	req := AdjustVolumeRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	volume := clamp(input.Volume+req.Delta, 0, 100)
+	vmix, _ := vmixSnapshot()
+	if err := SetVolume(vmix, input.Key, volume); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input":    input.Key,
+		"previous": input.Volume,
+		"volume":   volume,
+	})
+}
+
+// SetHeadphonesVolume sets headphones volume. value is clamped to 0 to 100.
+// vMix only exposes SetHeadphonesVolume for headphones. There is no function to turn them on or off.
+func SetHeadphonesVolume(v *vmixgo.Vmix, value float64) error {
+	params := make(map[string]string)
+	params["Value"] = formatFloat(clamp(value, 0, 100))
+	return sendFunction(v, "SetHeadphonesVolume", params)
+}
+
+// headphonesVolume returns headphones volume of master audio. ok is false if XML has no master audio.
+func headphonesVolume(v *vmixgo.Vmix) (volume float64, ok bool) {
+	if len(v.Audios.Master) == 0 {
+		return 0, false
+	}
+	return v.Audios.Master[0].HeadphonesVolume, true
+}
+
+// HeadphonesRequest Request JSON for SetHeadphonesHandler
+type HeadphonesRequest struct {
+	Volume *float64 `json:"volume"` // headphones volume. clamped to 0 to 100.
+}
+
+// GetHeadphonesHandler returns headphones volume for [GET] /api/audio/headphones .
+func GetHeadphonesHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
+	volume, ok := headphonesVolume(vmix)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Master audio not loaded",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"volume": volume,
+	})
+}
+
+// SetHeadphonesHandler sets headphones volume for [POST] /api/audio/headphones
+// and returns the resulting volume read back from vMix.
+func SetHeadphonesHandler(c *gin.Context) {
+	req := HeadphonesRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if req.Volume == nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "volume required",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	err := SetHeadphonesVolume(vmix, *req.Volume)
+	if err == nil {
+		err = refreshvMix()
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ = vmixSnapshot()
+	volume, _ := headphonesVolume(vmix)
+	c.JSON(http.StatusOK, gin.H{
+		"volume": volume,
+	})
+}
+
+// CrossfadeRequest Request JSON for CrossfadeHandler
+type CrossfadeRequest struct {
+	FromInput  string   `json:"fromInput"`  // input key, number or title faded down to 0.
+	ToInput    string   `json:"toInput"`    // input key, number or title faded up.
+	DurationMs uint     `json:"durationMs"` // fade duration in milliseconds. defaults to 500.
+	Volume     *float64 `json:"volume"`     // volume toInput is faded up to. defaults to 100.
+}
+
+// CrossfadeHandler fades audio of one input down and another up for [POST] /api/audio/crossfade .
+// Both fades are sent concurrently so they start together.
+func CrossfadeHandler(c *gin.Context) {
+	req := CrossfadeRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	from, ok := findInput(req.FromInput)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Input %q not found", req.FromInput),
+		})
+		return
+	}
+	to, ok := findInput(req.ToInput)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Input %q not found", req.ToInput),
+		})
+		return
+	}
+	if from.Key == to.Key {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "fromInput and toInput must be different",
+		})
+		return
+	}
+	duration := req.DurationMs
+	if duration == 0 {
+		duration = defaultTransitionDuration
+	}
+	volume := 100.0
+	if req.Volume != nil {
+		volume = clamp(*req.Volume, 0, 100)
+	}
+
+	errs := make(chan error, 2)
+	vmix, _ := vmixSnapshot()
+	go func() { errs <- SetVolumeFade(vmix, from.Key, 0, duration) }()
+	go func() { errs <- SetVolumeFade(vmix, to.Key, volume, duration) }()
+	var err error
+	for i := 0; i < 2; i++ {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"fromInput":  from.Key,
+		"toInput":    to.Key,
+		"volume":     volume,
+		"durationMs": duration,
+	})
+}
+
+// InputVolume is volume of a single input.
+type InputVolume struct {
+	Input  string  `json:"input"`  // input key, number or title.
+	Volume float64 `json:"volume"` // volume. clamped to 0 to 100.
+}
+
+// VolumeResult is a result of setting volume of a single input.
+type VolumeResult struct {
+	Input  string  `json:"input"`           // input key.
+	Volume float64 `json:"volume"`          // volume sent after clamping.
+	Error  string  `json:"error,omitempty"` // error message. empty on success.
+}
+
+// setVolumes sets volume of each input concurrently, bounded by maxConcurrentFunctions.
+// Inputs must be resolved to keys. status is 200 OK, or 202 Accepted if any function failed.
+func setVolumes(volumes []InputVolume) (results []VolumeResult, status int) {
+	results = make([]VolumeResult, len(volumes))
+	wg := &sync.WaitGroup{}
+	sem := make(chan struct{}, maxConcurrentFunctions)
+	vmix, _ := vmixSnapshot()
+	for i, v := range volumes {
+		results[i] = VolumeResult{Input: v.Input, Volume: clamp(v.Volume, 0, 100)}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem }()
+			defer wg.Done()
+			if err := SetVolume(vmix, results[i].Input, results[i].Volume); err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	status = http.StatusOK
+	for _, r := range results {
+		if r.Error != "" {
+			status = http.StatusAccepted
+			break
+		}
+	}
+	return results, status
+}
+
+// BulkVolumeRequest Request JSON for BulkVolumeHandler
+type BulkVolumeRequest struct {
+	Volumes []InputVolume `json:"volumes"`
+}
+
+// BulkVolumeHandler sets volume of several inputs for [POST] /api/audio/volume/bulk .
+// Every input is resolved before any function is sent.
+func BulkVolumeHandler(c *gin.Context) {
+	req := BulkVolumeRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if len(req.Volumes) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Volumes empty",
+		})
+		return
+	}
+	volumes := make([]InputVolume, 0, len(req.Volumes))
+	for _, v := range req.Volumes {
+		input, ok := findInput(v.Input)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Input %q not found", v.Input),
+			})
+			return
+		}
+		volumes = append(volumes, InputVolume{Input: input.Key, Volume: v.Volume})
+	}
+	results, status := setVolumes(volumes)
+	c.JSON(status, gin.H{
+		"results": results,
+	})
+}
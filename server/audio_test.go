@@ -0,0 +1,215 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetBalanceHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/audio/balance", `{"input":"CAM 1","balance":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "SetBalance" || calls[0].Query.Get("Value") != "1" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/balance", `{"input":"missing","balance":0}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSetPan(t *testing.T) {
+	s, _ := setupTest(t)
+	if err := SetPan(vmix, "1", -50); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Calls()[0].Query.Get("Value"); got != "-0.5" {
+		t.Errorf("Value = %s, want -0.5", got)
+	}
+}
+
+func TestNormalizeHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	// CAM 1 peaks at 0.1 (-20dBFS), gain 0dB.
+	w := doRequest(r, http.MethodPost, "/api/audio/normalize", `{"input":"CAM 1","target_db":-10}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("gain applied without apply:true")
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/normalize", `{"input":"CAM 1","target_db":-10,"apply":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "SetGain" || calls[0].Query.Get("Value") != "10" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/normalize", `{"input":"opener.mp4"}`)
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d for silent input", w.Code, http.StatusConflict)
+	}
+}
+
+func TestAudioMatrix(t *testing.T) {
+	s, r := setupTest(t)
+
+	w := doRequest(r, http.MethodGet, "/api/audio/matrix", "")
+	if !strings.Contains(w.Body.String(), `"buses":["master","A"]`) || !strings.Contains(w.Body.String(), `"buses":{"A":true,"master":true}`) {
+		t.Errorf("unexpected matrix: %s", w.Body.String())
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/matrix", `{"input":"1","bus":"master","on":false}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "AudioBusOff" || calls[0].Query.Get("Value") != "M" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	if w := doRequest(r, http.MethodPost, "/api/audio/matrix", `{"input":"1","bus":"H","on":true}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSetChannelMatrixHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	w := doRequest(r, http.MethodPost, "/api/inputs/1/channelmatrix", `{"preset":"SDI","routes":[{"channel":1,"source_channel":3},{"channel":2,"source_channel":4}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 3 || calls[0].Function != "AudioChannelMatrixApplyPreset" || calls[1].Query.Get("Value") != "1,3" || calls[2].Query.Get("Value") != "2,4" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	for _, body := range []string{`{}`, `{"routes":[{"channel":0,"source_channel":1}]}`, `{"routes":[{"channel":1,"source_channel":17}]}`} {
+		if w := doRequest(r, http.MethodPost, "/api/inputs/1/channelmatrix", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAdjustVolumeHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	for body, want := range map[string]string{
+		`{"input":"2","delta":5}`:   "85",
+		`{"input":"2","delta":-90}`: "0",
+		`{"input":"2","delta":50}`:  "100",
+	} {
+		s.Reset()
+		w := doRequest(r, http.MethodPost, "/api/audio/volume/adjust", body)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"volume":`+want) {
+			t.Errorf("%s: %d %s", body, w.Code, w.Body.String())
+		}
+		if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "SetVolume" || calls[0].Query.Get("Value") != want {
+			t.Errorf("%s: unexpected calls: %+v", body, calls)
+		}
+	}
+}
+
+func TestHeadphonesHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/audio/headphones", "")
+	if w.Code != http.StatusOK || w.Body.String() != `{"volume":74.36}` {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/headphones", `{"volume":150}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("len(Calls) = %d, want 1", len(calls))
+	}
+	if calls[0].Function != "SetHeadphonesVolume" || calls[0].Query.Get("Value") != "100" {
+		t.Errorf("volume not clamped: %v", calls[0].Query)
+	}
+	for _, body := range []string{`{}`, `{"on":false}`} {
+		if w := doRequest(r, http.MethodPost, "/api/audio/headphones", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCrossfadeHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/audio/crossfade", `{"fromInput":"CAM 1","toInput":"2","durationMs":1500,"volume":80}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	values := map[string]string{}
+	for _, c := range s.Calls() {
+		if c.Function != "SetVolumeFade" {
+			t.Errorf("unexpected function %s", c.Function)
+		}
+		values[c.Query.Get("Input")] = c.Query.Get("Value")
+	}
+	if values[vmix.Inputs.Input[0].Key] != "0,1500" || values[vmix.Inputs.Input[1].Key] != "80,1500" {
+		t.Errorf("unexpected fades: %v", values)
+	}
+
+	s.Reset()
+	for body, want := range map[string]int{
+		`{"fromInput":"1","toInput":"1"}`:       http.StatusBadRequest,
+		`{"fromInput":"missing","toInput":"1"}`: http.StatusNotFound,
+		`{"fromInput":"1","toInput":"missing"}`: http.StatusNotFound,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/audio/crossfade", body); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+}
+
+func TestBulkVolumeHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/audio/volume/bulk", `{"volumes":[{"input":"CAM 1","volume":60},{"input":"2","volume":120}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	values := map[string]string{}
+	for _, c := range s.Calls() {
+		if c.Function != "SetVolume" {
+			t.Errorf("unexpected function %s", c.Function)
+		}
+		values[c.Query.Get("Input")] = c.Query.Get("Value")
+	}
+	if values[vmix.Inputs.Input[0].Key] != "60" || values[vmix.Inputs.Input[1].Key] != "100" {
+		t.Errorf("unexpected volumes: %v", values)
+	}
+	if !strings.Contains(w.Body.String(), `{"input":"`+vmix.Inputs.Input[1].Key+`","volume":100}`) {
+		t.Errorf("clamped volume not reported: %s", w.Body.String())
+	}
+
+	s.Reset()
+	for body, want := range map[string]int{
+		`{"volumes":[]}`: http.StatusBadRequest,
+		`{"volumes":[{"input":"1","volume":10},{"input":"missing","volume":10}]}`: http.StatusNotFound,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/audio/volume/bulk", body); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	if w := doRequest(r, http.MethodPost, "/api/audio/volume/bulk", `{"volumes":[{"input":"1","volume":10}]}`); w.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// AudioLevel is captured audio state of an input.
+type AudioLevel struct {
+	Volume float64 `json:"volume"`
+	Muted  bool    `json:"muted"`
+}
+
+// AudioSnapshot is named audio state of inputs, keyed by input key so it survives input renumbering.
+type AudioSnapshot struct {
+	Name    string                `json:"name"`
+	Inputs  map[string]AudioLevel `json:"inputs"` // input key to audio state.
+	Created time.Time             `json:"created"`
+}
+
+// Validate form
+func (s *AudioSnapshot) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("Snapshot name empty")
+	}
+	return nil
+}
+
+// audioSnapshotStore holds audio snapshots in memory only. They are lost on restart unless exported by /api/config/export .
+type audioSnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]AudioSnapshot
+}
+
+var audioSnapshots = &audioSnapshotStore{snapshots: map[string]AudioSnapshot{}}
+
+func (s *audioSnapshotStore) list() []AudioSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshots := make([]AudioSnapshot, 0, len(s.snapshots))
+	for _, snap := range s.snapshots {
+		snapshots = append(snapshots, snap)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+func (s *audioSnapshotStore) get(name string) (AudioSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[name]
+	return snap, ok
+}
+
+func (s *audioSnapshotStore) put(snap AudioSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[snap.Name] = snap
+}
+
+func (s *audioSnapshotStore) remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.snapshots[name]; !ok {
+		return false
+	}
+	delete(s.snapshots, name)
+	return true
+}
+
+// replace replaces all snapshots by snapshots.
+func (s *audioSnapshotStore) replace(snapshots []AudioSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = map[string]AudioSnapshot{}
+	for _, snap := range snapshots {
+		s.snapshots[snap.Name] = snap
+	}
+}
+
+// captureAudio returns current audio state of every input.
+func captureAudio(name string) AudioSnapshot {
+	snap := AudioSnapshot{Name: name, Inputs: map[string]AudioLevel{}, Created: time.Now()}
+	vmix, _ := vmixSnapshot()
+	for _, input := range vmix.Inputs.Input {
+		snap.Inputs[input.Key] = AudioLevel{Volume: input.Volume, Muted: input.Muted}
+	}
+	return snap
+}
+
+// GetAudioSnapshotsHandler returns audio snapshots for [GET] /api/audio/snapshots .
+func GetAudioSnapshotsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"snapshots": audioSnapshots.list(),
+	})
+}
+
+// GetAudioSnapshotHandler returns audio snapshot for [GET] /api/audio/snapshots/:name .
+func GetAudioSnapshotHandler(c *gin.Context) {
+	snap, ok := audioSnapshots.get(c.Param("name"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Snapshot not found",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, snap)
+}
+
+// AudioSnapshotRequest Request JSON for SaveAudioSnapshotHandler
+type AudioSnapshotRequest struct {
+	Name string `json:"name"` // snapshot name. existing snapshot with the same name is overwritten.
+}
+
+// SaveAudioSnapshotHandler captures current volume and mute of every input for [POST] /api/audio/snapshots .
+func SaveAudioSnapshotHandler(c *gin.Context) {
+	req := AudioSnapshotRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Snapshot name empty",
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	snap := captureAudio(req.Name)
+	audioSnapshots.put(snap)
+	c.JSON(http.StatusCreated, snap)
+}
+
+// DeleteAudioSnapshotHandler removes audio snapshot for [DELETE] /api/audio/snapshots/:name .
+func DeleteAudioSnapshotHandler(c *gin.Context) {
+	if !audioSnapshots.remove(c.Param("name")) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Snapshot not found",
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RecallAudioSnapshotHandler applies audio snapshot for [POST] /api/audio/snapshots/:name/recall .
+// Volumes are set by bulk set, and mute is toggled only on inputs whose mute differs.
+// Inputs in the snapshot which no longer exist are reported as "missing".
+func RecallAudioSnapshotHandler(c *gin.Context) {
+	snap, ok := audioSnapshots.get(c.Param("name"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Snapshot not found",
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	volumes := []InputVolume{}
+	mute, unmute := []vmixgo.Input{}, []vmixgo.Input{}
+	found := map[string]bool{}
+	vmix, _ := vmixSnapshot()
+	for _, input := range vmix.Inputs.Input {
+		level, ok := snap.Inputs[input.Key]
+		if !ok {
+			continue
+		}
+		found[input.Key] = true
+		volumes = append(volumes, InputVolume{Input: input.Key, Volume: level.Volume})
+		if level.Muted && !input.Muted {
+			mute = append(mute, input)
+		} else if !level.Muted && input.Muted {
+			unmute = append(unmute, input)
+		}
+	}
+	missing := []string{}
+	for key := range snap.Inputs {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	results, status := setVolumes(volumes)
+	muteResults, muteStatus := sendToInputs(mute, "AudioOff", nil)
+	unmuteResults, unmuteStatus := sendToInputs(unmute, "AudioOn", nil)
+	if muteStatus != http.StatusOK || unmuteStatus != http.StatusOK {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, gin.H{
+		"name":         snap.Name,
+		"results":      results,
+		"mute_results": append(muteResults, unmuteResults...),
+		"missing":      missing,
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestAudioSnapshotHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	audioSnapshots.replace(nil)
+	defer audioSnapshots.replace(nil)
+
+	w := doRequest(r, http.MethodPost, "/api/audio/snapshots", `{"name":"interview"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	opener := vmix.Inputs.Input[1].Key
+	if !strings.Contains(w.Body.String(), `"`+opener+`":{"volume":80,"muted":true}`) {
+		t.Errorf("unexpected snapshot: %s", w.Body.String())
+	}
+	if w := doRequest(r, http.MethodGet, "/api/audio/snapshots/interview", ""); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// opener gets unmuted and turned down, and CAM 1 is removed.
+	xml := strings.Replace(vmixtest.DefaultXML, `muted="True" volume="80"`, `muted="False" volume="30"`, 1)
+	xml = xml[:strings.Index(xml, `<input key="0b1a9c6e-1111`)] + xml[strings.Index(xml, `<input key="0b1a9c6e-2222`):]
+	s.SetXML(xml)
+	w = doRequest(r, http.MethodPost, "/api/audio/snapshots/interview/recall", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	functions := map[string]string{}
+	for _, c := range s.Calls() {
+		if c.Query.Get("Input") == opener {
+			functions[c.Function] = c.Query.Get("Value")
+		}
+	}
+	if v, ok := functions["SetVolume"]; !ok || v != "80" {
+		t.Errorf("volume not recalled: %v", functions)
+	}
+	if _, ok := functions["AudioOff"]; !ok {
+		t.Errorf("mute not recalled: %v", functions)
+	}
+	if !strings.Contains(w.Body.String(), `"missing":["0b1a9c6e-1111-4c7a-9a53-6f2b4d1e0001"]`) {
+		t.Errorf("missing input not reported: %s", w.Body.String())
+	}
+
+	w = doRequest(r, http.MethodGet, "/api/config/export", "")
+	if !strings.Contains(w.Body.String(), `"audio_snapshots":[{"name":"interview"`) {
+		t.Errorf("snapshot not exported: %s", w.Body.String())
+	}
+
+	if w := doRequest(r, http.MethodDelete, "/api/audio/snapshots/interview", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	for method, path := range map[string]string{
+		http.MethodGet:    "/api/audio/snapshots/interview",
+		http.MethodDelete: "/api/audio/snapshots/interview",
+		http.MethodPost:   "/api/audio/snapshots/interview/recall",
+	} {
+		if w := doRequest(r, method, path, ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s %s: status = %d, want %d", method, path, w.Code, http.StatusNotFound)
+		}
+	}
+	if w := doRequest(r, http.MethodPost, "/api/audio/snapshots", `{"name":""}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// normalizeBasePath returns -base-path with a leading slash and without trailing slash. e.g. "vmix/" -> "/vmix" .
+// Empty or "/" serves from root and returns "".
+func normalizeBasePath(p string) (string, error) {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return "", nil
+	}
+	if strings.ContainsAny(p, "?#:*\\") || strings.Contains(p, "//") {
+		return "", fmt.Errorf("Invalid base path %q", p)
+	}
+	return "/" + p, nil
+}
+
+var (
+	baseHrefTag = regexp.MustCompile(`(?i)<base\s[^>]*>`)
+	headTag     = regexp.MustCompile(`(?i)<head[^>]*>`)
+	htmlTag     = regexp.MustCompile(`(?i)<html[^>]*>`)
+)
+
+// withBaseHref sets <base href> of index.html to base path, so relative paths of the frontend resolve under it.
+// An existing <base> tag is replaced. Otherwise it is inserted at the top of <head>, or of <html> if there is no <head>.
+func withBaseHref(index []byte, base string) []byte {
+	tag := []byte(`<base href="` + html.EscapeString(base+"/") + `">`)
+	if baseHrefTag.Match(index) {
+		return baseHrefTag.ReplaceAllLiteral(index, tag)
+	}
+	for _, re := range []*regexp.Regexp{headTag, htmlTag} {
+		if loc := re.FindIndex(index); loc != nil {
+			b := make([]byte, 0, len(index)+len(tag))
+			b = append(b, index[:loc[1]]...)
+			b = append(b, tag...)
+			return append(b, index[loc[1]:]...)
+		}
+	}
+	return append(tag, index...)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":       "",
+		"/":      "",
+		"vmix":   "/vmix",
+		"/vmix/": "/vmix",
+		"/a/b":   "/a/b",
+	} {
+		if got, err := normalizeBasePath(in); err != nil || got != want {
+			t.Errorf("normalizeBasePath(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"/a//b", "/:key", "/a?b"} {
+		if _, err := normalizeBasePath(in); err == nil {
+			t.Errorf("normalizeBasePath(%q) returned no error", in)
+		}
+	}
+}
+
+func TestWithBaseHref(t *testing.T) {
+	for in, want := range map[string]string{
+		`<html><head><title>x</title></head></html>`: `<html><head><base href="/vmix/"><title>x</title></head></html>`,
+		`<html><head><base href="/"></head></html>`:  `<html><head><base href="/vmix/"></head></html>`,
+		`<html></html>`: `<html><base href="/vmix/"></html>`,
+		`<!DOCTYPE html><HEAD lang="en"></HEAD><p></p>`: `<!DOCTYPE html><HEAD lang="en"><base href="/vmix/"></HEAD><p></p>`,
+	} {
+		if got := string(withBaseHref([]byte(in), "/vmix")); got != want {
+			t.Errorf("withBaseHref(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestOpenAPIHandlerBasePath(t *testing.T) {
+	r := gin.New()
+	api := r.Group("/vmix/api")
+	registerAPI(api)
+	api.GET("/openapi.json", OpenAPIHandler(r.Routes))
+
+	w := doRequest(r, http.MethodGet, "/vmix/api/openapi.json", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), `"/vmix/api/inputs/{key}/fields"`) {
+		t.Errorf("spec does not contain routes under base path: %s", w.Body.String()[:200])
+	}
+}
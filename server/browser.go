@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// isTerminal reports whether f is an interactive terminal.
+// Services such as NSSM redirect stdout to a file or pipe.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// shouldOpenBrowser reports whether the browser should be opened on startup.
+func shouldOpenBrowser() bool {
+	return *openBrowser && !*noBrowser && isTerminal(os.Stdout)
+}
+
+// openURL opens url in default browser. only Windows is supported.
+func openURL(url string) {
+	if runtime.GOOS != "windows" {
+		log.Printf("Opening browser is not supported on %s. Open %s manually\n", runtime.GOOS, url)
+		return
+	}
+	if err := exec.Command("rundll32.exe", "url.dll,FileProtocolHandler", url).Start(); err != nil {
+		log.Println("Failed to open link. ignoring...")
+		log.Printf("ERR : %v\n", err)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// maxAudioChannel is the number of audio channels in vMix channel matrix. SDI embeds up to 16 channels.
+const maxAudioChannel = 16
+
+// SetChannelMatrix routes source channel of input audio to channel. channels start from 1.
+func SetChannelMatrix(v *vmixgo.Vmix, input string, channel, sourceChannel int) error {
+	if channel < 1 || channel > maxAudioChannel || sourceChannel < 1 || sourceChannel > maxAudioChannel {
+		return fmt.Errorf("channels must be between 1 and %d", maxAudioChannel)
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = strconv.Itoa(channel) + "," + strconv.Itoa(sourceChannel)
+	return sendFunction(v, "SetChannelMatrix", params)
+}
+
+// AudioChannelMatrixApplyPreset applies channel matrix preset saved in vMix to input.
+func AudioChannelMatrixApplyPreset(v *vmixgo.Vmix, input string, preset string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = preset
+	return sendFunction(v, "AudioChannelMatrixApplyPreset", params)
+}
+
+// ChannelRoute is a route of channel matrix.
+type ChannelRoute struct {
+	Channel       int `json:"channel"`        // destination channel. 1 to 16.
+	SourceChannel int `json:"source_channel"` // source channel of input audio. 1 to 16.
+}
+
+// ChannelMatrixRequest Request JSON for SetChannelMatrixHandler
+type ChannelMatrixRequest struct {
+	Preset string         `json:"preset"` // optional preset applied before routes.
+	Routes []ChannelRoute `json:"routes"`
+}
+
+// Validate form
+func (r *ChannelMatrixRequest) Validate() error {
+	if r.Preset == "" && len(r.Routes) == 0 {
+		return fmt.Errorf("preset or routes required")
+	}
+	for i, route := range r.Routes {
+		if route.Channel < 1 || route.Channel > maxAudioChannel {
+			return fmt.Errorf("routes[%d] : channel must be between 1 and %d", i, maxAudioChannel)
+		}
+		if route.SourceChannel < 1 || route.SourceChannel > maxAudioChannel {
+			return fmt.Errorf("routes[%d] : source_channel must be between 1 and %d", i, maxAudioChannel)
+		}
+	}
+	return nil
+}
+
+// SetChannelMatrixHandler sets audio channel matrix of input for [POST] /api/inputs/:key/channelmatrix .
+func SetChannelMatrixHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := ChannelMatrixRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if req.Preset != "" {
+		if err := AudioChannelMatrixApplyPreset(vmix, input.Key, req.Preset); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	for _, route := range req.Routes {
+		if err := SetChannelMatrix(vmix, input.Key, route.Channel, route.SourceChannel); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input":  input.Key,
+		"preset": req.Preset,
+		"routes": req.Routes,
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// coalescedCall is a function call shared by identical sends within the window.
+type coalescedCall struct {
+	done    chan struct{} // closed when err is set
+	err     error
+	expires time.Time // window end, counted from the first send
+}
+
+// functionCoalescer merges identical function sends within window into a single vMix call,
+// such as a double-clicked or bouncing button. Only applied to /api/function, as other handlers send repeats on purpose.
+// window 0 disables it. Set by -coalesce-window flag.
+type functionCoalescer struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[string]*coalescedCall
+}
+
+var coalescedFunctions = &functionCoalescer{entries: map[string]*coalescedCall{}}
+
+// coalesceKey identifies function by vMix host, name and params.
+func coalesceKey(v *vmixgo.Vmix, funcname string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(v.Addr.String())
+	b.WriteString("\x00" + funcname)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + params[k])
+	}
+	return b.String()
+}
+
+// do runs fn once per key within window. Later calls with the same key wait for and return the first result.
+func (c *functionCoalescer) do(key string, fn func() error) error {
+	c.mu.Lock()
+	if c.window <= 0 {
+		c.mu.Unlock()
+		return fn()
+	}
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.err
+	}
+	e := &coalescedCall{done: make(chan struct{}), expires: now.Add(c.window)}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.err = fn()
+	close(e.done)
+	return e.err
+}
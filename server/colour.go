@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// colourFunction is a vMix colour correction function and its valid value range.
+type colourFunction struct {
+	Function string
+	Min, Max float64
+}
+
+// Colour correction functions, keyed by SetColourRequest field.
+// vMix has no contrast function, so contrast is not supported.
+var colourFunctions = map[string]colourFunction{
+	"red":        {"SetCCGainR", 0, 2},
+	"green":      {"SetCCGainG", 0, 2},
+	"blue":       {"SetCCGainB", 0, 2},
+	"brightness": {"SetCCGainY", 0, 2},
+	"hue":        {"SetCCHue", -180, 180},
+	"saturation": {"SetCCSaturation", -1, 1},
+}
+
+// colourNames are colourFunctions keys in the order SetColourHandler applies them.
+var colourNames = []string{"red", "green", "blue", "brightness", "hue", "saturation"}
+
+// checkColour returns error if value is out of range of colour function name.
+func checkColour(name string, value float64) error {
+	f := colourFunctions[name]
+	if value < f.Min || value > f.Max {
+		return fmt.Errorf("%s must be %s to %s", name, formatFloat(f.Min), formatFloat(f.Max))
+	}
+	return nil
+}
+
+func setColour(v *vmixgo.Vmix, name string, input string, value float64) error {
+	if err := checkColour(name, value); err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(value)
+	return sendFunction(v, colourFunctions[name].Function, params)
+}
+
+// SetColourRed sets red gain of input. 0 to 2, 1 is neutral.
+func SetColourRed(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "red", input, value)
+}
+
+// SetColourGreen sets green gain of input. 0 to 2, 1 is neutral.
+func SetColourGreen(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "green", input, value)
+}
+
+// SetColourBlue sets blue gain of input. 0 to 2, 1 is neutral.
+func SetColourBlue(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "blue", input, value)
+}
+
+// SetBrightness sets luma gain of input. 0 to 2, 1 is neutral.
+func SetBrightness(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "brightness", input, value)
+}
+
+// SetHue rotates hue of input. -180 to 180 degrees.
+func SetHue(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "hue", input, value)
+}
+
+// SetSaturation sets saturation of input. -1 to 1, 0 is neutral.
+func SetSaturation(v *vmixgo.Vmix, input string, value float64) error {
+	return setColour(v, "saturation", input, value)
+}
+
+// SetColourRequest Request JSON for SetColourHandler. omitted values are not changed.
+type SetColourRequest struct {
+	Red        *float64 `json:"red"`
+	Green      *float64 `json:"green"`
+	Blue       *float64 `json:"blue"`
+	Brightness *float64 `json:"brightness"`
+	Hue        *float64 `json:"hue"`
+	Saturation *float64 `json:"saturation"`
+}
+
+// values returns specified values keyed by colourFunctions key.
+func (r *SetColourRequest) values() map[string]float64 {
+	values := map[string]float64{}
+	for name, p := range map[string]*float64{
+		"red":        r.Red,
+		"green":      r.Green,
+		"blue":       r.Blue,
+		"brightness": r.Brightness,
+		"hue":        r.Hue,
+		"saturation": r.Saturation,
+	} {
+		if p != nil {
+			values[name] = *p
+		}
+	}
+	return values
+}
+
+// Validate form
+func (r *SetColourRequest) Validate() error {
+	values := r.values()
+	if len(values) == 0 {
+		return fmt.Errorf("No colour values specified")
+	}
+	for name, value := range values {
+		if err := checkColour(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetColourHandler sets colour correction of input for [POST] /api/inputs/:key/colour .
+// Values are applied in colourNames order. If vMix fails, "applied" lists values already applied.
+func SetColourHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := SetColourRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	values := req.values()
+	applied := []string{}
+	for _, name := range colourNames {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if err := setColour(vmix, name, input.Key, value); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   err.Error(),
+				"applied": applied,
+			})
+			return
+		}
+		applied = append(applied, name)
+	}
+	c.JSON(http.StatusOK, req)
+}
+
+// hexColour matches "#RRGGBB" or "#AARRGGBB", "#" optional.
+var hexColour = regexp.MustCompile(`^#?([0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+
+// vMixColour converts hex colour to "#AARRGGBB" expected by vMix. "#RRGGBB" is opaque.
+func vMixColour(hex string) (string, error) {
+	if !hexColour.MatchString(hex) {
+		return "", fmt.Errorf("Invalid colour %q. must be #RRGGBB or #AARRGGBB", hex)
+	}
+	hex = strings.ToUpper(strings.TrimPrefix(hex, "#"))
+	if len(hex) == 6 {
+		hex = "FF" + hex
+	}
+	return "#" + hex, nil
+}
+
+// SetColor sets colour of text or rectangle element selectedName in title input. hexColour is "#RRGGBB" or "#AARRGGBB".
+func SetColor(v *vmixgo.Vmix, input string, selectedName string, hexColour string) error {
+	colour, err := vMixColour(hexColour)
+	if err != nil {
+		return err
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["SelectedName"] = selectedName
+	params["Value"] = colour
+	return sendFunction(v, "SetColor", params)
+}
+
+// ColourFieldRequest Request JSON for SetColourFieldHandler
+type ColourFieldRequest struct {
+	Name   string `json:"name"`   // title element name. e.g. "Background.Fill.Color" .
+	Colour string `json:"colour"` // "#RRGGBB" or "#AARRGGBB".
+}
+
+// Validate form
+func (r *ColourFieldRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("Field name empty")
+	}
+	_, err := vMixColour(r.Colour)
+	return err
+}
+
+// SetColourFieldHandler sets colour of title element for [POST] /api/inputs/:key/colour-field .
+func SetColourFieldHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := ColourFieldRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := SetColor(vmix, input.Key, req.Name, req.Colour); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	colour, _ := vMixColour(req.Colour)
+	c.JSON(http.StatusOK, gin.H{
+		"input":  input.Key,
+		"name":   req.Name,
+		"colour": colour,
+	})
+}
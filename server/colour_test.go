@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetColourHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/1/colour", `{"red":1.2,"saturation":-0.5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 || calls[0].Function != "SetCCGainR" || calls[0].Query.Get("Value") != "1.2" ||
+		calls[1].Function != "SetCCSaturation" || calls[1].Query.Get("Value") != "-0.5" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	s.Reset()
+	for _, body := range []string{`{}`, `{"hue":200}`, `{"red":1,"blue":-1}`} {
+		if w := doRequest(r, http.MethodPost, "/api/inputs/1/colour", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent for invalid request")
+	}
+
+	// Stops at the first failure and reports what was applied.
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	w = doRequest(r, http.MethodPost, "/api/inputs/1/colour", `{"red":1.2,"saturation":-0.5}`)
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"applied":[]`) {
+		t.Errorf("failure not reported: %d %s", w.Code, w.Body.String())
+	}
+	if len(s.Calls()) != 1 {
+		t.Errorf("len(Calls) = %d after failure, want 1", len(s.Calls()))
+	}
+}
+
+func TestVMixColour(t *testing.T) {
+	for in, want := range map[string]string{
+		"#1e90ff":   "#FF1E90FF",
+		"1E90FF":    "#FF1E90FF",
+		"#801e90ff": "#801E90FF",
+	} {
+		if got, err := vMixColour(in); err != nil || got != want {
+			t.Errorf("vMixColour(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "#fff", "#1e90fg", "#1e90ff0", "red"} {
+		if _, err := vMixColour(in); err == nil {
+			t.Errorf("vMixColour(%q) returned no error", in)
+		}
+	}
+}
+
+func TestSetColourFieldHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/3/colour-field", `{"name":"Background.Fill.Color","colour":"#c8102e"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("len(Calls) = %d, want 1", len(calls))
+	}
+	if q := calls[0].Query; calls[0].Function != "SetColor" || q.Get("SelectedName") != "Background.Fill.Color" || q.Get("Value") != "#FFC8102E" {
+		t.Errorf("unexpected call: %v", q)
+	}
+
+	s.Reset()
+	for _, body := range []string{`{"name":"Background.Fill.Color","colour":"crimson"}`, `{"colour":"#c8102e"}`} {
+		if w := doRequest(r, http.MethodPost, "/api/inputs/3/colour-field", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// configBundleVersion is the current ConfigBundle format version.
+const configBundleVersion = 1
+
+// ConfigBundle is exported utility configuration.
+// Only settings kept by the server are included. vMix address and other flags are not.
+// The server keeps these settings in memory only and never writes them to disk,
+// so the bundle is the way to keep them across restarts.
+type ConfigBundle struct {
+	Version  int                      `json:"version"`                        // bundle format version.
+	Webhooks []Webhook                `json:"webhooks"`                       // registered webhooks.
+	Usage    map[string]FunctionUsage `json:"usage,omitempty"`                // function usage stats.
+	Timeouts map[string]int           `json:"function_timeouts_ms,omitempty"` // per-function timeouts in ms overriding builtin ones.
+	Audio    []AudioSnapshot          `json:"audio_snapshots,omitempty"`      // saved audio snapshots.
+	Overlays []OverlayLayout          `json:"overlay_presets,omitempty"`      // saved overlay layouts.
+}
+
+// Validate form
+func (b *ConfigBundle) Validate() error {
+	if b.Version < 1 || b.Version > configBundleVersion {
+		return fmt.Errorf("Unsupported bundle version %d", b.Version)
+	}
+	for i := range b.Webhooks {
+		if err := b.Webhooks[i].Validate(); err != nil {
+			return fmt.Errorf("webhooks[%d] : %v", i, err)
+		}
+	}
+	names := map[string]bool{}
+	for i := range b.Audio {
+		if err := b.Audio[i].Validate(); err != nil {
+			return fmt.Errorf("audio_snapshots[%d] : %v", i, err)
+		}
+		if names[b.Audio[i].Name] {
+			return fmt.Errorf("audio_snapshots[%d] : Duplicate name %q", i, b.Audio[i].Name)
+		}
+		names[b.Audio[i].Name] = true
+	}
+	names = map[string]bool{}
+	for i := range b.Overlays {
+		if err := b.Overlays[i].Validate(); err != nil {
+			return fmt.Errorf("overlay_presets[%d] : %v", i, err)
+		}
+		if names[b.Overlays[i].Name] {
+			return fmt.Errorf("overlay_presets[%d] : Duplicate name %q", i, b.Overlays[i].Name)
+		}
+		names[b.Overlays[i].Name] = true
+	}
+	for f, ms := range b.Timeouts {
+		if ms <= 0 {
+			return fmt.Errorf("function_timeouts_ms[%s] must be positive", f)
+		}
+	}
+	return nil
+}
+
+// exportConfig returns current configuration.
+func exportConfig() ConfigBundle {
+	return ConfigBundle{
+		Version:  configBundleVersion,
+		Webhooks: webhooks.list(),
+		Usage:    functionUsage.get(),
+		Timeouts: functionTimeouts.overridesMs(),
+		Audio:    audioSnapshots.list(),
+		Overlays: overlayLayouts.list(),
+	}
+}
+
+// importConfig replaces current configuration by b. b must be validated.
+func importConfig(b ConfigBundle) {
+	webhooks.replace(b.Webhooks)
+	functionUsage.replace(b.Usage)
+	functionTimeouts.replace(b.Timeouts)
+	audioSnapshots.replace(b.Audio)
+	overlayLayouts.replace(b.Overlays)
+}
+
+// ExportConfigHandler returns configuration bundle for [GET] /api/config/export .
+func ExportConfigHandler(c *gin.Context) {
+	c.Header("Content-Disposition", `attachment; filename="vmix-utility-config.json"`)
+	c.JSON(http.StatusOK, exportConfig())
+}
+
+// ImportConfigHandler loads configuration bundle for [POST] /api/config/import .
+// Previous configuration is returned as "backup" so it can be imported again.
+func ImportConfigHandler(c *gin.Context) {
+	b := ConfigBundle{}
+	if err := c.ShouldBindJSON(&b); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := b.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	backup := exportConfig()
+	importConfig(b)
+	c.JSON(http.StatusOK, gin.H{
+		"imported": exportConfig(),
+		"backup":   backup,
+	})
+}
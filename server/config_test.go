@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImportConfigHandler(t *testing.T) {
+	_, r := setupTest(t)
+	webhooks.replace(nil)
+	defer webhooks.replace(nil)
+	defer overlayLayouts.replace(nil)
+
+	w := doRequest(r, http.MethodPost, "/api/config/import", `{"version":1,"webhooks":[{"url":"http://example.com/hook","events":["input_live"]}],"overlay_presets":[{"name":"show","channels":{"1":"lower","2":""}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if hooks := webhooks.list(); len(hooks) != 1 || hooks[0].URL != "http://example.com/hook" || hooks[0].ID == "" {
+		t.Errorf("unexpected webhooks: %+v", hooks)
+	}
+	if layout, ok := overlayLayouts.get("show"); !ok || layout[1] != "lower" || layout[2] != "" {
+		t.Errorf("unexpected overlay preset: %v", layout)
+	}
+
+	w = doRequest(r, http.MethodGet, "/api/config/export", "")
+	if !strings.Contains(w.Body.String(), `"version":1`) || !strings.Contains(w.Body.String(), "http://example.com/hook") {
+		t.Errorf("unexpected export: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"overlay_presets":[{"name":"show","channels":{"1":"lower","2":""}}]`) {
+		t.Errorf("overlay presets not exported: %s", w.Body.String())
+	}
+
+	for _, body := range []string{
+		`{"version":99,"webhooks":[]}`,
+		`{"version":1,"webhooks":[{"url":"http://example.com","events":["unknown"]}]}`,
+		`{"version":1,"overlay_presets":[{"name":"show","channels":{"5":"lower"}}]}`,
+		`{"version":1,"overlay_presets":[{"name":"show"},{"name":"show"}]}`,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/config/import", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(webhooks.list()) != 1 {
+		t.Error("invalid bundle replaced configuration")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// connectHook starts a vMix script when vMix becomes reachable, to bootstrap show state.
+type connectHook struct {
+	mu        sync.Mutex
+	script    string // vMix script name. empty disables.
+	reconnect bool   // run again after reconnects, not only after the first connection.
+	connected bool
+	ran       bool
+}
+
+// onConnect is set by -on-connect and -on-connect-reconnect flags.
+var onConnect = &connectHook{}
+
+// refreshed records result of XML refresh, and starts the script in background
+// when vMix became reachable. Script errors are logged and never block serving.
+func (h *connectHook) refreshed(v *vmixgo.Vmix, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.connected = false
+		return
+	}
+	if h.connected {
+		return
+	}
+	h.connected = true
+	if h.script == "" || (h.ran && !h.reconnect) {
+		return
+	}
+	h.ran = true
+	go h.run(v, h.script)
+}
+
+func (h *connectHook) run(v *vmixgo.Vmix, script string) {
+	if err := ScriptStart(v, script); err != nil {
+		log.Printf("Failed to start on-connect script %s : %v\n", script, err)
+		return
+	}
+	log.Printf("Started on-connect script %s\n", script)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+// waitCalls waits until s received n calls or a second passes.
+func waitCalls(s *vmixtest.Server, n int) []vmixtest.Call {
+	deadline := time.Now().Add(time.Second)
+	for {
+		calls := s.Calls()
+		if len(calls) >= n || time.Now().After(deadline) {
+			return calls
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConnectHook(t *testing.T) {
+	s, _ := setupTest(t)
+	disconnected := fmt.Errorf("Failed to connect vmix")
+
+	for _, reconnect := range []bool{false, true} {
+		s.Reset()
+		h := &connectHook{script: "Init", reconnect: reconnect}
+		h.refreshed(vmix, nil)
+		calls := waitCalls(s, 1)
+		if len(calls) != 1 || calls[0].Function != "ScriptStart" || calls[0].Query.Get("Value") != "Init" {
+			t.Fatalf("reconnect=%v: unexpected calls on connect: %+v", reconnect, calls)
+		}
+		h.refreshed(vmix, nil)
+		h.refreshed(vmix, disconnected)
+		h.refreshed(vmix, nil)
+		want := 1
+		if reconnect {
+			want = 2
+		}
+		waitCalls(s, want)
+		time.Sleep(50 * time.Millisecond)
+		if got := len(s.Calls()); got != want {
+			t.Errorf("reconnect=%v: len(Calls) = %d, want %d", reconnect, got, want)
+		}
+	}
+
+	// script errors are only logged
+	s.Reset()
+	s.SetFunctionResponse(http.StatusInternalServerError, "")
+	h := &connectHook{script: "Init"}
+	h.refreshed(vmix, nil)
+	if calls := waitCalls(s, 1); len(calls) != 1 {
+		t.Errorf("len(Calls) = %d, want 1", len(calls))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XMLSnapshot is raw vMix API XML fetched by a refresh.
+type XMLSnapshot struct {
+	Time time.Time `json:"time"`
+	XML  string    `json:"xml"` // response body as received, before decimal normalization.
+}
+
+// xmlHistory keeps raw XML of the last two refreshes to find elements the model does not parse.
+// Nothing is kept unless enabled by -debug flag.
+type xmlHistory struct {
+	mu       sync.Mutex
+	enabled  bool
+	previous *XMLSnapshot
+	current  *XMLSnapshot
+}
+
+var rawXML = &xmlHistory{}
+
+// record keeps body as current snapshot and moves current one to previous.
+func (h *xmlHistory) record(body []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.enabled {
+		return
+	}
+	h.previous, h.current = h.current, &XMLSnapshot{Time: time.Now(), XML: string(body)}
+}
+
+func (h *xmlHistory) get() (enabled bool, previous, current *XMLSnapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.enabled, h.previous, h.current
+}
+
+// GetDebugXMLHandler returns raw XML of previous and current refresh for [GET] /api/debug/xml .
+// Snapshots are null until that many refreshes happened after startup.
+func GetDebugXMLHandler(c *gin.Context) {
+	enabled, previous, current := rawXML.get()
+	if !enabled {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Debug disabled. Start with -debug to keep raw XML",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"previous": previous,
+		"current":  current,
+	})
+}
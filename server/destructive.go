@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// destructiveFunctions are functions which can lose work, such as removing inputs or overwriting presets.
+// /api/function, /api/multiple and /api/inputs/bulk require "confirm": true for them. Set by -destructive-functions flag.
+var destructiveFunctions = map[string]bool{
+	"RemoveInput": true,
+	"CloseVmix":   true,
+	"SavePreset":  true,
+	"OpenPreset":  true,
+}
+
+// destructiveFunctionNames returns comma separated destructive functions, for default flag value.
+func destructiveFunctionNames() string {
+	names := make([]string, 0, len(destructiveFunctions))
+	for name := range destructiveFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// setDestructiveFunctions replaces destructiveFunctions by comma separated names.
+func setDestructiveFunctions(names string) {
+	destructiveFunctions = map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			destructiveFunctions[name] = true
+		}
+	}
+}
+
+// isDestructive reports whether funcname requires confirmation. vMix function names are case-insensitive.
+func isDestructive(funcname string) bool {
+	for name := range destructiveFunctions {
+		if strings.EqualFold(name, funcname) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+const (
+	discoverTimeout     = 500 * time.Millisecond // per host timeout
+	discoverConcurrency = 64                     // hosts probed at once
+	discoverMaxHosts    = 1024                   // largest network to scan
+)
+
+// DiscoveredvMix is a vMix instance found by discovery.
+type DiscoveredvMix struct {
+	Addr    string `json:"addr"`    // vMix API address. e.g. "http://192.168.1.10:8088" .
+	Version string `json:"version"` // vMix version.
+	Edition string `json:"edition"` // vMix edition.
+}
+
+// probevMix checks whether addr serves vMix API XML.
+func probevMix(client *http.Client, addr string) (DiscoveredvMix, bool) {
+	resp, err := client.Get(addr + "/api")
+	if err != nil {
+		return DiscoveredvMix{}, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return DiscoveredvMix{}, false
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return DiscoveredvMix{}, false
+	}
+	v := vmixgo.Vmix{}
+	if err := xml.Unmarshal(body, &v); err != nil || v.Version == "" {
+		return DiscoveredvMix{}, false
+	}
+	return DiscoveredvMix{Addr: addr, Version: v.Version, Edition: v.Edition}, true
+}
+
+// localNetworks returns IPv4 networks of local interfaces.
+// Networks larger than discoverMaxHosts, such as docker0 172.17.0.0/16 or a /22 LAN, are narrowed
+// to the /24 around the interface address, and the original networks are returned as narrowed.
+func localNetworks() (networks []*net.IPNet, narrowed []string, err error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, nil, err
+	}
+	networks = []*net.IPNet{}
+	narrowed = []string{}
+	seen := map[string]bool{}
+	for _, a := range addrs {
+		n, ok := a.(*net.IPNet)
+		if !ok || n.IP.IsLoopback() || n.IP.To4() == nil {
+			continue
+		}
+		full := &net.IPNet{IP: n.IP.To4().Mask(n.Mask), Mask: n.Mask}
+		network := localNetwork(n.IP.To4(), n.Mask)
+		if network.String() != full.String() {
+			narrowed = append(narrowed, full.String())
+		}
+		if !seen[network.String()] {
+			seen[network.String()] = true
+			networks = append(networks, network)
+		}
+	}
+	return networks, narrowed, nil
+}
+
+// localNetwork returns network of interface address ip, narrowed to the /24 around ip if it is larger than discoverMaxHosts.
+func localNetwork(ip net.IP, mask net.IPMask) *net.IPNet {
+	if ones, bits := mask.Size(); bits == 32 && 1<<uint(bits-ones) > discoverMaxHosts {
+		mask = net.CIDRMask(24, 32)
+	}
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// networkHosts returns host addresses in IPv4 network n.
+func networkHosts(n *net.IPNet) ([]net.IP, error) {
+	ones, bits := n.Mask.Size()
+	if bits != 32 {
+		return nil, fmt.Errorf("%s is not IPv4 network", n)
+	}
+	size := 1 << uint(bits-ones)
+	if size > discoverMaxHosts {
+		return nil, fmt.Errorf("%s is too large to scan. maximum %d hosts", n, discoverMaxHosts)
+	}
+	base := n.IP.To4()
+	hosts := []net.IP{}
+	for i := 0; i < size; i++ {
+		// skip network and broadcast address
+		if size > 2 && (i == 0 || i == size-1) {
+			continue
+		}
+		ip := make(net.IP, 4)
+		v := uint32(base[0])<<24 | uint32(base[1])<<16 | uint32(base[2])<<8 | uint32(base[3]) + uint32(i)
+		ip[0], ip[1], ip[2], ip[3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+		hosts = append(hosts, ip)
+	}
+	return hosts, nil
+}
+
+// DiscoverHandler scans network for vMix for [GET] /api/discover .
+// "cidr" query specifies network to scan, local networks are scanned if omitted. "port" defaults to 8088.
+// "cidr" larger than discoverMaxHosts returns 400. Local networks that large are narrowed to the /24
+// around this host and listed in "narrowed".
+func DiscoverHandler(c *gin.Context) {
+	port, err := strconv.Atoi(c.DefaultQuery("port", "8088"))
+	if err != nil || port <= 0 || port > 65535 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid port",
+		})
+		return
+	}
+
+	networks := []*net.IPNet{}
+	narrowed := []string{}
+	if cidr := c.Query("cidr"); cidr != "" {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		networks = append(networks, n)
+	} else {
+		networks, narrowed, err = localNetworks()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	hosts := []net.IP{}
+	for _, n := range networks {
+		h, err := networkHosts(n)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		hosts = append(hosts, h...)
+	}
+
+	client := &http.Client{Timeout: discoverTimeout}
+	found := []DiscoveredvMix{}
+	m := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	sem := make(chan struct{}, discoverConcurrency)
+	for _, ip := range hosts {
+		addr := fmt.Sprintf("http://%s", net.JoinHostPort(ip.String(), strconv.Itoa(port)))
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			defer wg.Done()
+			if d, ok := probevMix(client, addr); ok {
+				m.Lock()
+				found = append(found, d)
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, gin.H{
+		"vmix":     found,
+		"narrowed": narrowed,
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestProbevMix(t *testing.T) {
+	s := vmixtest.NewServer(vmixtest.DefaultXML)
+	defer s.Close()
+
+	d, ok := probevMix(http.DefaultClient, s.URL)
+	if !ok {
+		t.Fatal("vMix not detected")
+	}
+	if d.Version != "24.0.0.72" || d.Edition != "4K" {
+		t.Errorf("unexpected result: %+v", d)
+	}
+
+	s.SetXML("<html></html>")
+	if _, ok := probevMix(http.DefaultClient, s.URL); ok {
+		t.Error("non-vMix response detected as vMix")
+	}
+}
+
+func TestNetworkHosts(t *testing.T) {
+	_, n, _ := net.ParseCIDR("192.168.1.0/30")
+	hosts, err := networkHosts(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 2 || hosts[0].String() != "192.168.1.1" || hosts[1].String() != "192.168.1.2" {
+		t.Errorf("unexpected hosts: %v", hosts)
+	}
+
+	_, n, _ = net.ParseCIDR("10.0.0.0/8")
+	if _, err := networkHosts(n); err == nil {
+		t.Error("expected error for large network")
+	}
+}
+
+func TestLocalNetwork(t *testing.T) {
+	for _, tt := range []struct {
+		ip   string
+		ones int
+		want string
+	}{
+		{"192.168.1.20", 24, "192.168.1.0/24"},
+		{"192.168.5.20", 22, "192.168.4.0/22"},
+		{"192.168.5.20", 21, "192.168.5.0/24"},
+		{"172.17.3.1", 16, "172.17.3.0/24"},
+		{"10.1.2.3", 8, "10.1.2.0/24"},
+	} {
+		if got := localNetwork(net.ParseIP(tt.ip).To4(), net.CIDRMask(tt.ones, 32)); got.String() != tt.want {
+			t.Errorf("localNetwork(%s/%d) = %s, want %s", tt.ip, tt.ones, got, tt.want)
+		}
+	}
+}
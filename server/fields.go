@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// SetFieldRequest is a title field value to set.
+type SetFieldRequest struct {
+	Name   string `json:"name"`   // field name. e.g. "Headline.Text" .
+	Index  *int   `json:"index"`  // field index used instead of name if name is empty.
+	Value  string `json:"value"`  // raw value.
+	Format string `json:"format"` // optional format. printf format with one verb such as "%03d" or "$%.2f", or "thousands" . empty sends raw value. there is no currency format, write the symbol as literal text.
+	Mode   string `json:"mode"`   // "set", "append" or "prepend" formatted value to current value. empty means "set".
+}
+
+// SetFieldsRequest Request JSON for SetFieldsHandler
+type SetFieldsRequest struct {
+	Fields []SetFieldRequest `json:"fields"`
+}
+
+// FieldResult is a result of setting single title field.
+type FieldResult struct {
+	Name  string `json:"name"`            // field name.
+	Index *int   `json:"index,omitempty"` // field index if set by index.
+	Value string `json:"value"`           // value sent to vMix.
+	Mode  string `json:"mode"`            // mode actually used. "set" if current value could not be read.
+	Error string `json:"error,omitempty"` // error message. empty on success.
+}
+
+// groupThousands inserts "," every 3 digits of integer part. e.g. "-1250.5" -> "-1,250.5" .
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	frac := ""
+	if i := strings.Index(s, "."); i >= 0 {
+		s, frac = s[:i], s[i:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s + frac
+}
+
+// fieldFormatVerb returns the single printf verb of format such as "%03d", "$%.2f" or "%.1f pts".
+// Literal text around the verb and "%%" are allowed. Anything else, such as no verb, two verbs,
+// "*" width or an unknown verb, is an error so a garbled value is never sent to a title.
+func fieldFormatVerb(format string) (byte, error) {
+	var verb byte
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i++
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+		if i == len(format) || strings.IndexByte("dxXobcfFeEgGsqv", format[i]) < 0 {
+			return 0, fmt.Errorf("Invalid format %q. must contain a single verb such as %%d, %%.2f or %%s", format)
+		}
+		if verb != 0 {
+			return 0, fmt.Errorf("Invalid format %q. must contain only one verb", format)
+		}
+		verb = format[i]
+	}
+	if verb == 0 {
+		return 0, fmt.Errorf("Invalid format %q. must contain a single verb such as %%d, %%.2f or %%s", format)
+	}
+	return verb, nil
+}
+
+// validateFieldFormat returns error if format is neither empty, "thousands" nor a valid printf format.
+func validateFieldFormat(format string) error {
+	if format == "" || format == "thousands" {
+		return nil
+	}
+	_, err := fieldFormatVerb(format)
+	return err
+}
+
+// formatFieldValue formats raw value by format.
+func formatFieldValue(value, format string) (string, error) {
+	if format == "" {
+		return value, nil
+	}
+	if format == "thousands" {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+		return groupThousands(value), nil
+	}
+	verb, err := fieldFormatVerb(format)
+	if err != nil {
+		return "", err
+	}
+	switch verb {
+	case 'd', 'x', 'X', 'o', 'b', 'c':
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		return fmt.Sprintf(format, i), nil
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a number", value)
+		}
+		return fmt.Sprintf(format, f), nil
+	default:
+		return fmt.Sprintf(format, value), nil
+	}
+}
+
+// params returns SetText params for field of input.
+func (f SetFieldRequest) params(input string) map[string]string {
+	params := make(map[string]string)
+	params["Input"] = input
+	if f.Name == "" && f.Index != nil {
+		params["SelectedIndex"] = strconv.Itoa(*f.Index)
+	} else {
+		params["SelectedName"] = f.Name
+	}
+	return params
+}
+
+// current returns current value of the field in input.
+func (f SetFieldRequest) current(input InputExtra) (string, bool) {
+	if f.Name == "" && f.Index != nil {
+		return input.textAt(*f.Index)
+	}
+	return input.text(f.Name)
+}
+
+// fieldModes are valid SetFieldRequest modes.
+var fieldModes = map[string]bool{"": true, "set": true, "append": true, "prepend": true}
+
+// currentFields reads current title field values of input from vMix.
+// ok is false if vMix could not be read.
+func currentFields(key string) (input InputExtra, ok bool) {
+	if err := refreshvMix(); err != nil {
+		return InputExtra{}, false
+	}
+	_, vmixExtra := vmixSnapshot()
+	return vmixExtra.input(key)
+}
+
+// SetFieldsHandler sets title fields of input for [POST] /api/inputs/:key/fields .
+func SetFieldsHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := SetFieldsRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var current *InputExtra
+	for _, f := range req.Fields {
+		if !fieldModes[f.Mode] {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Unknown mode %q for field %s", f.Mode, f.Name),
+			})
+			return
+		}
+		if f.Name == "" && f.Index == nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Field name or index required",
+			})
+			return
+		}
+		if err := validateFieldFormat(f.Format); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err := validateSelectedIndex("SetText", f.params(input.Key)); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if current == nil && (f.Mode == "append" || f.Mode == "prepend") {
+			// Read once for all fields. Failed read leaves empty InputExtra so fields fall back to "set".
+			i, _ := currentFields(input.Key)
+			current = &i
+		}
+	}
+
+	results := make([]FieldResult, 0, len(req.Fields))
+	status := http.StatusOK
+	vmix, _ := vmixSnapshot()
+	for _, f := range req.Fields {
+		r := FieldResult{Name: f.Name, Index: f.Index, Mode: "set"}
+		value, err := formatFieldValue(f.Value, f.Format)
+		if err == nil {
+			if f.Mode == "append" || f.Mode == "prepend" {
+				if text, ok := f.current(*current); ok {
+					r.Mode = f.Mode
+					if f.Mode == "append" {
+						value = text + value
+					} else {
+						value = value + text
+					}
+				}
+			}
+			r.Value = value
+			params := f.params(input.Key)
+			params["Value"] = value
+			err = sendFunction(vmix, "SetText", params)
+		}
+		if err != nil {
+			r.Error = err.Error()
+			status = http.StatusAccepted
+		}
+		results = append(results, r)
+	}
+	c.JSON(status, gin.H{
+		"results": results,
+	})
+}
+
+// SetFieldVisible shows or hides title field of input.
+// Image fields are sent as SetImageVisibleOn/Off and others as SetTextVisibleOn/Off.
+func SetFieldVisible(v *vmixgo.Vmix, input string, field string, image bool, visible bool) error {
+	function := "SetTextVisible"
+	if image {
+		function = "SetImageVisible"
+	}
+	if visible {
+		function += "On"
+	} else {
+		function += "Off"
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["SelectedName"] = field
+	return sendFunction(v, function, params)
+}
+
+// VisibilityRequest Request JSON for SetVisibilityHandler
+type VisibilityRequest struct {
+	Fields map[string]bool `json:"fields"` // field name to visibility. e.g. {"Sponsor.Source":false} .
+}
+
+// VisibilityResult is a result of showing or hiding single title field.
+type VisibilityResult struct {
+	Name    string `json:"name"`            // field name.
+	Visible bool   `json:"visible"`         // requested visibility.
+	Error   string `json:"error,omitempty"` // error message. empty on success.
+}
+
+// SetVisibilityHandler shows or hides title fields of input for [POST] /api/inputs/:key/visibility .
+// Every field is validated against fields of the input before any function is sent.
+func SetVisibilityHandler(c *gin.Context) {
+	req := VisibilityRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if len(req.Fields) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Fields empty",
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, vmixExtra := vmixSnapshot()
+	extra, _ := vmixExtra.input(input.Key)
+	images := map[string]bool{}
+	for _, f := range extra.Image {
+		images[f.Name] = true
+	}
+
+	names := make([]string, 0, len(req.Fields))
+	for name := range req.Fields {
+		if _, ok := extra.text(name); !ok && !images[name] {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Unknown field %q", name),
+			})
+			return
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]VisibilityResult, 0, len(names))
+	status := http.StatusOK
+	for _, name := range names {
+		r := VisibilityResult{Name: name, Visible: req.Fields[name]}
+		if err := SetFieldVisible(vmix, input.Key, name, images[name], r.Visible); err != nil {
+			r.Error = err.Error()
+			status = http.StatusAccepted
+		}
+		results = append(results, r)
+	}
+	c.JSON(status, gin.H{
+		"input":   input.Key,
+		"results": results,
+	})
+}
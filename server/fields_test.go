@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestFormatFieldValue(t *testing.T) {
+	for _, tt := range []struct {
+		value, format, want string
+		err                 bool
+	}{
+		{"7", "", "7", false},
+		{"7", "%03d", "007", false},
+		{"1250", "thousands", "1,250", false},
+		{"-1234567.5", "thousands", "-1,234,567.5", false},
+		{"3.14159", "%.2f", "3.14", false},
+		{"abc", "%03d", "", true},
+		{"1", "currency", "", true},
+		{"1250.5", "$%.2f", "$1250.50", false},
+		{"3.5", "%.2f €", "3.50 €", false},
+		{"7", "%d pts", "7 pts", false},
+		{"50", "%d%%", "50%", false},
+		{"Bob", "Name: %s", "Name: Bob", false},
+		{"7", "%d%d", "", true},
+		{"7", "pts", "", true},
+		{"7", "%*d", "", true},
+		{"7", "%z", "", true},
+		{"7", "100%", "", true},
+	} {
+		got, err := formatFieldValue(tt.value, tt.format)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("formatFieldValue(%q, %q) = %q, %v", tt.value, tt.format, got, err)
+		}
+	}
+}
+
+func TestSetFieldsHandlerInvalidFormat(t *testing.T) {
+	s, r := setupTest(t)
+	for _, format := range []string{"%d%d", "currency", "%d pts %s"} {
+		body := `{"fields":[{"name":"Headline.Text","value":"1"},{"name":"Headline.Text","value":"7","format":"` + format + `"}]}`
+		if w := doRequest(r, http.MethodPost, "/api/inputs/3/fields", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, want %d", format, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Errorf("functions sent for invalid format: %+v", s.Calls())
+	}
+}
+
+func TestSetFieldsHandlerAppend(t *testing.T) {
+	s, r := setupTest(t)
+
+	body := `{"fields":[{"name":"Headline.Text","value":", World","mode":"append"},{"name":"Headline.Text","value":">> ","mode":"prepend"},{"name":"Missing.Text","value":"x","mode":"append"}]}`
+	w := doRequest(r, http.MethodPost, "/api/inputs/3/fields", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	for i, want := range []string{"Hello, World", ">> Hello", "x"} {
+		if got := calls[i].Query.Get("Value"); got != want {
+			t.Errorf("calls[%d] Value = %q, want %q", i, got, want)
+		}
+	}
+	if !strings.Contains(w.Body.String(), `"mode":"set"`) {
+		t.Errorf("missing field should fall back to set: %s", w.Body.String())
+	}
+
+	// Unreadable vMix falls back to set.
+	s.Reset()
+	s.SetXML("broken")
+	defer s.SetXML(vmixtest.DefaultXML)
+	doRequest(r, http.MethodPost, "/api/inputs/3/fields", `{"fields":[{"name":"Headline.Text","value":"Bye","mode":"append"}]}`)
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Query.Get("Value") != "Bye" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	if w := doRequest(r, http.MethodPost, "/api/inputs/3/fields", `{"fields":[{"name":"Headline.Text","mode":"replace"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSetVisibilityHandler(t *testing.T) {
+	s, r := setupTest(t)
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, `Hello</text>`, `Hello</text><image index="1" name="Sponsor.Source">C:\sponsor.png</image>`, 1))
+
+	if w := doRequest(r, http.MethodPost, "/api/inputs/3/visibility", `{"fields":{"Missing.Text":true}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown field: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Fatalf("functions sent for unknown field: %+v", calls)
+	}
+
+	w := doRequest(r, http.MethodPost, "/api/inputs/3/visibility", `{"fields":{"Headline.Text":true,"Sponsor.Source":false}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	for i, want := range [][2]string{{"SetTextVisibleOn", "Headline.Text"}, {"SetImageVisibleOff", "Sponsor.Source"}} {
+		if q := calls[i].Query; q.Get("Function") != want[0] || q.Get("SelectedName") != want[1] || q.Get("Input") != vmix.Inputs.Input[2].Key {
+			t.Errorf("call %d = %v, want %s %s", i, q, want[0], want[1])
+		}
+	}
+}
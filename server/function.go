@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// maxQueryLength is the longest query string sent by GET. Longer functions are sent by POST form.
+const maxQueryLength = 2000
+
+// Default vMix HTTP transport settings. Overridden by -vmix-max-idle-conns and -vmix-idle-timeout flags.
+const (
+	defaultvMixMaxIdleConns = 16
+	defaultvMixIdleTimeout  = 90 * time.Second
+)
+
+// newvMixTransport returns HTTP transport tuned for bursts of small requests to a single vMix host.
+// Connections are kept alive and reused, and compression is disabled as vMix responses are small.
+func newvMixTransport(maxIdleConns int, idleTimeout time.Duration) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConns,
+		IdleConnTimeout:     idleTimeout,
+		DisableCompression:  true,
+	}
+}
+
+// vmixClient is HTTP client used for every request to vMix.
+var vmixClient = &http.Client{
+	Transport: newvMixTransport(defaultvMixMaxIdleConns, defaultvMixIdleTimeout),
+}
+
+// functionErrorMarkers are texts in vMix function response body which mean the function failed,
+// as vMix may respond 200 OK even if it failed. Matched case-insensitively. Set by -error-markers flag.
+var functionErrorMarkers = []string{"Function Failed", "Function not found", "Input not found"}
+
+// functionResponseError returns error if body contains any of functionErrorMarkers.
+func functionResponseError(body []byte) error {
+	b := strings.ToLower(string(body))
+	for _, m := range functionErrorMarkers {
+		if m != "" && strings.Contains(b, strings.ToLower(m)) {
+			return fmt.Errorf("vMix returned error : %s", strings.TrimSpace(string(body)))
+		}
+	}
+	return nil
+}
+
+// sendFunction sends function to vMix and records the result in vmixStatus, functionMetrics and functionUsage.
+func sendFunction(v *vmixgo.Vmix, funcname string, params map[string]string) error {
+	start := time.Now()
+	err := doSendFunction(v, funcname, params)
+	functionMetrics.record(funcname, err, time.Since(start))
+	vmixStatus.recordFunction(err)
+	if err == nil {
+		functionUsage.record(funcname)
+	}
+	return err
+}
+
+// functionRequest returns URL and POST form of function request to vMix.
+// If the query is too long for an URL, such as ScriptStartDynamic code, it is sent as POST form and form is non-empty.
+func functionRequest(v *vmixgo.Vmix, funcname string, params map[string]string) (u url.URL, form string) {
+	q := v.Addr.Query()
+	q.Add("Function", funcname)
+	for k, v := range params {
+		q.Add(k, v)
+	}
+	encoded := q.Encode()
+
+	u = *v.Addr
+	if len(encoded) > maxQueryLength {
+		u.RawQuery = ""
+		return u, encoded
+	}
+	u.RawQuery = encoded
+	return u, ""
+}
+
+// doSendFunction sends request to /api?Function=funcname&Key=Value... .
+// The request times out after functionTimeouts of funcname.
+func doSendFunction(v *vmixgo.Vmix, funcname string, params map[string]string) error {
+	u, form := functionRequest(v, funcname, params)
+	ctx, cancel := context.WithTimeout(context.Background(), functionTimeouts.get(funcname))
+	defer cancel()
+	var req *http.Request
+	var err error
+	if form != "" {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(form))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to send function... %v", err)
+	}
+	resp, err := vmixClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to send function... %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySnippet))
+		return &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       strings.TrimSpace(string(snippet)),
+		}
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed to Read body... %v", err)
+	}
+	return functionResponseError(body)
+}
+
+// shellQuote quotes s for POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// exportFunction formats function call as format. format is "url", "curl" or "go".
+func exportFunction(v *vmixgo.Vmix, format string, funcname string, params map[string]string) (string, error) {
+	u, form := functionRequest(v, funcname, params)
+	switch format {
+	case "url":
+		if form != "" {
+			return "", fmt.Errorf("Function is too long for URL and is sent as POST form. use curl format")
+		}
+		return u.String(), nil
+	case "curl":
+		if form != "" {
+			return fmt.Sprintf("curl -X POST --data %s %s", shellQuote(form), shellQuote(u.String())), nil
+		}
+		return fmt.Sprintf("curl %s", shellQuote(u.String())), nil
+	case "go":
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kvs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			kvs = append(kvs, fmt.Sprintf("%s: %s", strconv.Quote(k), strconv.Quote(params[k])))
+		}
+		return fmt.Sprintf("vmix.SendFunction(%s, map[string]string{%s})", strconv.Quote(funcname), strings.Join(kvs, ", ")), nil
+	default:
+		return "", fmt.Errorf("Unknown format %q. valid formats: url, curl, go", format)
+	}
+}
+
+// ExportFunctionHandler returns function call as code without sending it for [GET] /api/function/export .
+// "function" and "format" queries are required, and other queries except "token" are sent to vMix as function queries.
+// e.g. /api/function/export?format=curl&function=Fade&Input=1&Duration=500
+func ExportFunctionHandler(c *gin.Context) {
+	funcname := strings.TrimSpace(c.Query("function"))
+	if funcname == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Function empty",
+		})
+		return
+	}
+	params := make(map[string]string)
+	for k, vs := range c.Request.URL.Query() {
+		if k == "function" || k == "format" || k == "token" || len(vs) == 0 {
+			continue
+		}
+		params[k] = vs[0]
+	}
+	vmix, _ := vmixSnapshot()
+	code, err := exportFunction(vmix, c.DefaultQuery("format", "url"), funcname, params)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.String(http.StatusOK, code)
+}
+
+// FunctionRequest Request JSON for DoFunctionHandler
+type FunctionRequest struct {
+	Function string          `json:"function"` // function name. e.g. "Fade" .
+	Queries  []FunctionQuery `json:"queries"`  // Key-Value queries.
+	Verify   *VerifyRequest  `json:"verify"`   // optional state to verify after sending.
+	Confirm  bool            `json:"confirm"`  // required for destructiveFunctions.
+}
+
+// Validate form
+func (r *FunctionRequest) Validate() error {
+	if strings.TrimSpace(r.Function) == "" {
+		return fmt.Errorf("Function empty")
+	}
+	for _, v := range r.Queries {
+		if v.Key == "" || v.Value == "" {
+			return fmt.Errorf("Invalid queries")
+		}
+	}
+	if r.Verify != nil {
+		if err := r.Verify.resolve(r.Function); err != nil {
+			return err
+		}
+	}
+	return validateSelectedIndex(r.Function, r.Params())
+}
+
+// Params returns queries as function params.
+func (r *FunctionRequest) Params() map[string]string {
+	params := make(map[string]string)
+	for _, v := range r.Queries {
+		params[v.Key] = v.Value
+	}
+	return params
+}
+
+// DoFunctionHandler sends a function to vMix for [POST] /api/function .
+// destructiveFunctions return 428 unless "confirm" is true.
+// Identical functions within -coalesce-window, such as a double-clicked button, share one vMix call.
+// With "verify", vMix state is polled after sending and "verified" reports whether it became the expected value.
+// Requests with the same Idempotency-Key header within -idempotency-ttl return the first result
+// with "Idempotent-Replayed: true" header instead of sending the function again.
+func DoFunctionHandler(c *gin.Context) {
+	req := FunctionRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if isDestructive(req.Function) && !req.Confirm {
+		c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{
+			"error": fmt.Sprintf("%s is destructive. Send again with \"confirm\": true", req.Function),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	params := req.Params()
+	send := func() (int, gin.H) {
+		err := coalescedFunctions.do(coalesceKey(vmix, req.Function, params), func() error {
+			return sendFunction(vmix, req.Function, params)
+		})
+		if err != nil {
+			return http.StatusInternalServerError, gin.H{"error": err.Error()}
+		}
+		if req.Verify == nil {
+			return http.StatusOK, gin.H{"function": req.Function}
+		}
+		verified, actual := verifyState(*req.Verify)
+		return http.StatusOK, gin.H{
+			"function": req.Function,
+			"verified": verified,
+			"field":    req.Verify.Field,
+			"expected": req.Verify.Value,
+			"actual":   actual,
+		}
+	}
+
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		status, body := send()
+		c.JSON(status, body)
+		return
+	}
+	// keys are scoped per vMix host
+	status, body, replayed := idempotencyKeys.do(vmix.Addr.String()+"\x00"+key, send)
+	if replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
+	c.JSON(status, body)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestSendFunctionLargeValue(t *testing.T) {
+	s, _ := setupTest(t)
+	code := strings.Repeat("Dim i As Integer = 1\n", 500)
+
+	if err := sendFunction(vmix, "ScriptStartDynamic", map[string]string{"Value": code}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sendFunction(vmix, "Cut", map[string]string{"Input": "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Method != http.MethodPost || calls[0].Query.Get("Value") != code {
+		t.Errorf("large function sent by %s, value intact: %v", calls[0].Method, calls[0].Query.Get("Value") == code)
+	}
+	if calls[1].Method != http.MethodGet {
+		t.Errorf("small function sent by %s, want GET", calls[1].Method)
+	}
+}
+
+func TestDoFunctionHandlerCoalesce(t *testing.T) {
+	s, r := setupTest(t)
+	defer func() { coalescedFunctions.window = 0 }()
+
+	send := func(n int, input string) {
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w := doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut","queries":[{"key":"Input","value":"`+input+`"}]}`)
+				if w.Code != http.StatusOK {
+					t.Errorf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	// Disabled by default.
+	send(2, "1")
+	if n := len(s.Calls()); n != 2 {
+		t.Errorf("len(Calls) = %d without window, want 2", n)
+	}
+
+	s.Reset()
+	coalescedFunctions.window = time.Minute
+	send(3, "1")
+	send(1, "2")
+	if n := len(s.Calls()); n != 2 {
+		t.Fatalf("len(Calls) = %d with window, want 2", n)
+	}
+
+	// Internal sends are never coalesced.
+	s.Reset()
+	w := doRequest(r, http.MethodPost, "/api/multiple", `{"function":"Cut","queries":[{"key":"Input","value":"1"}],"num":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if n := len(s.Calls()); n != 3 {
+		t.Errorf("len(Calls) = %d on /api/multiple with window, want 3", n)
+	}
+}
+
+func TestSendFunctionErrorResponse(t *testing.T) {
+	s, _ := setupTest(t)
+	for _, body := range []string{"Function Failed", "function not found", "Input not found: CAM 9"} {
+		s.SetFunctionResponse(http.StatusOK, body)
+		if err := sendFunction(vmix, "Cut", map[string]string{"Input": "1"}); err == nil {
+			t.Errorf("no error for 200 %q", body)
+		}
+	}
+
+	for _, status := range []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusInternalServerError} {
+		s.SetFunctionResponse(status, "Function completed successfully.")
+		err := sendFunction(vmix, "Cut", map[string]string{"Input": "1"})
+		if e, ok := err.(*HTTPStatusError); !ok || e.StatusCode != status {
+			t.Errorf("error for %d = %v, want HTTPStatusError", status, err)
+		}
+	}
+
+	s.SetFunctionResponse(http.StatusOK, "Function completed successfully.")
+	if err := sendFunction(vmix, "Cut", map[string]string{"Input": "1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExportFunctionHandler(t *testing.T) {
+	s, r := setupTest(t)
+	for format, want := range map[string]string{
+		"url":  s.URL + "/api?Duration=500&Function=Fade&Input=1",
+		"curl": "curl '" + s.URL + "/api?Duration=500&Function=Fade&Input=1'",
+		"go":   `vmix.SendFunction("Fade", map[string]string{"Duration": "500", "Input": "1"})`,
+	} {
+		w := doRequest(r, http.MethodGet, "/api/function/export?format="+format+"&function=Fade&Input=1&Duration=500", "")
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Errorf("%s: %d %s, want %s", format, w.Code, w.Body.String(), want)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("export sent function to vMix")
+	}
+
+	w := doRequest(r, http.MethodGet, "/api/function/export?format=python&function=Fade", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+// BenchmarkSendFunctionBurst sends bursts of 100 cuts with Go default transport and the tuned vMix transport.
+func BenchmarkSendFunctionBurst(b *testing.B) {
+	s := vmixtest.NewServer(vmixtest.DefaultXML)
+	defer s.Close()
+	v, _ := url.Parse(s.URL + "/api")
+	target := &vmixgo.Vmix{Addr: v}
+
+	for name, transport := range map[string]http.RoundTripper{
+		"default": &http.Transport{},
+		"tuned":   newvMixTransport(defaultvMixMaxIdleConns, defaultvMixIdleTimeout),
+	} {
+		b.Run(name, func(b *testing.B) {
+			prev := vmixClient.Transport
+			vmixClient.Transport = transport
+			defer func() { vmixClient.Transport = prev }()
+
+			for i := 0; i < b.N; i++ {
+				wg := &sync.WaitGroup{}
+				sem := make(chan struct{}, maxConcurrentFunctions)
+				for j := 0; j < 100; j++ {
+					wg.Add(1)
+					sem <- struct{}{}
+					go func() {
+						defer func() { <-sem }()
+						defer wg.Done()
+						sendFunction(target, "Cut", map[string]string{"Input": "1"})
+					}()
+				}
+				wg.Wait()
+				s.Reset()
+			}
+		})
+	}
+}
+
+func TestDoFunctionHandlerIdempotencyKey(t *testing.T) {
+	s, r := setupTest(t)
+	body := `{"function":"Cut","queries":[{"key":"Input","value":"2"}]}`
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/function", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "take-42")
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		if replayed := w.Header().Get("Idempotent-Replayed") == "true"; replayed != (i > 0) {
+			t.Errorf("request %d: replayed = %v", i, replayed)
+		}
+	}
+	if len(s.Calls()) != 1 {
+		t.Errorf("len(Calls) = %d, want 1", len(s.Calls()))
+	}
+
+	doRequest(r, http.MethodPost, "/api/function", body)
+	if len(s.Calls()) != 2 {
+		t.Errorf("request without key was not sent")
+	}
+}
+
+func TestFunctionTimeouts(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("Function completed successfully."))
+	}))
+	defer slow.Close()
+	u, _ := url.Parse(slow.URL + "/api")
+	v := &vmixgo.Vmix{Addr: u}
+
+	defer functionTimeouts.replace(nil)
+	functionTimeouts.replace(map[string]int{"Cut": 20})
+	if err := doSendFunction(v, "Cut", nil); err == nil {
+		t.Error("Cut did not time out")
+	}
+	if err := doSendFunction(v, "Fade", nil); err != nil {
+		t.Errorf("Fade with default timeout failed: %v", err)
+	}
+	if got := functionTimeouts.get("OpenPreset"); got != builtinFunctionTimeouts["OpenPreset"] {
+		t.Errorf("OpenPreset timeout = %v", got)
+	}
+}
+
+func TestDestructiveFunctionConfirm(t *testing.T) {
+	s, r := setupTest(t)
+
+	for path, body := range map[string]string{
+		"/api/function": `{"function":"removeinput","queries":[{"key":"Input","value":"2"}]}`,
+		"/api/multiple": `{"function":"RemoveInput","queries":[{"key":"Input","value":"2"}],"num":1}`,
+	} {
+		if w := doRequest(r, http.MethodPost, path, body); w.Code != http.StatusPreconditionRequired {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, http.StatusPreconditionRequired)
+		}
+		confirmed := strings.Replace(body, `{"function"`, `{"confirm":true,"function"`, 1)
+		if w := doRequest(r, http.MethodPost, path, confirmed); w.Code != http.StatusOK {
+			t.Errorf("%s: confirmed status = %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+	if calls := s.Calls(); len(calls) != 2 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	defer setDestructiveFunctions(destructiveFunctionNames())
+	setDestructiveFunctions("Cut, ")
+	if isDestructive("RemoveInput") || !isDestructive("Cut") {
+		t.Errorf("unexpected destructive functions: %v", destructiveFunctions)
+	}
+}
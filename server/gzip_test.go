@@ -0,0 +1,67 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	s, _ := setupTest(t)
+	// 50-input preset
+	inputs := &strings.Builder{}
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(inputs, `<input key="00000000-0000-0000-0000-%012d" number="%d" type="Capture" title="CAM %d" shortTitle="CAM %d" state="Running" position="0" duration="0" loop="False" muted="False" volume="100" balance="0" solo="False" audiobusses="M" meterF1="0" meterF2="0">CAM %d</input>`, i, i, i, i, i)
+	}
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<inputs>", "<inputs>"+inputs.String(), 1))
+	if err := refreshvMix(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	registerAPI(r.Group("/api", GzipMiddleware(true)))
+
+	plain := doRequest(r, http.MethodGet, "/api/inputs", "")
+	if plain.Header().Get("Content-Encoding") != "" {
+		t.Fatal("response compressed without Accept-Encoding")
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/inputs", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	r.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("response not compressed: %v", w.Header())
+	}
+	compressed := w.Body.Len()
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != plain.Body.String() {
+		t.Error("decompressed body differs from plain response")
+	}
+	t.Logf("/api/inputs with %d inputs : %d bytes -> %d bytes gzipped (%.0f%% smaller)", len(vmix.Inputs.Input), plain.Body.Len(), compressed, 100-float64(compressed)*100/float64(plain.Body.Len()))
+	if compressed*2 > plain.Body.Len() {
+		t.Errorf("gzip saved less than half: %d -> %d", plain.Body.Len(), compressed)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/api/functions/usage", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("empty response was compressed: %d %v %d bytes", w.Code, w.Header(), w.Body.Len())
+	}
+}
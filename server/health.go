@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// HostError is the most recent error of vMix host.
+type HostError struct {
+	Error string    `json:"error"` // error message.
+	Time  time.Time `json:"time"`  // when error occurred.
+}
+
+// hostStatus tracks connectivity of vMix host by XML refreshes, and last function error separately.
+// A failed function does not mean the host is unreachable, so it does not change connectivity.
+type hostStatus struct {
+	mu                sync.Mutex
+	lastError         *HostError
+	lastFunctionError *HostError
+	connectedSince    time.Time
+	lastRefresh       time.Time
+}
+
+// refreshed records successful XML refresh.
+func (s *hostStatus) refreshed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRefresh = time.Now()
+}
+
+// LastRefresh returns when XML was refreshed successfully last time. zero if never.
+func (s *hostStatus) LastRefresh() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRefresh
+}
+
+// record stores err of XML refresh as last error. nil err clears it.
+// Any error also resets the time host has been reachable since.
+func (s *hostStatus) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.lastError = nil
+		if s.connectedSince.IsZero() {
+			s.connectedSince = time.Now()
+		}
+		return
+	}
+	s.lastError = &HostError{Error: err.Error(), Time: time.Now()}
+	s.connectedSince = time.Time{}
+}
+
+// recordFunction stores err of function as last function error. nil err keeps the previous one.
+func (s *hostStatus) recordFunction(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastFunctionError = &HostError{Error: err.Error(), Time: time.Now()}
+}
+
+// LastFunctionError returns last function error. nil if no function failed.
+func (s *hostStatus) LastFunctionError() *HostError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastFunctionError
+}
+
+// ConnectedSince returns since when host has been reachable without errors. zero if last request failed.
+func (s *hostStatus) ConnectedSince() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connectedSince
+}
+
+// LastError returns last refresh error. nil if last refresh succeeded.
+func (s *hostStatus) LastError() *HostError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastError
+}
+
+// vmixStatus is status of the target vMix host.
+var vmixStatus = &hostStatus{}
+
+// GetHealthHandler returns vMix host health for [GET] /api/health .
+// "ok" reflects the last XML refresh. Failed functions are reported in "last_function_error" only.
+func GetHealthHandler(c *gin.Context) {
+	lastError := vmixStatus.LastError()
+	c.JSON(http.StatusOK, gin.H{
+		"url":                 *vmixaddr,
+		"ok":                  lastError == nil,
+		"last_error":          lastError,
+		"last_function_error": vmixStatus.LastFunctionError(),
+	})
+}
+
+// presetName returns file name of vMix preset path such as `C:\show.vmix` . empty if preset is not saved.
+func presetName(preset string) string {
+	return preset[strings.LastIndexAny(preset, `\/`)+1:]
+}
+
+// stateWarnings returns non-fatal warnings about vMix state which is likely a mistake.
+func stateWarnings(v *vmixgo.Vmix) []string {
+	warnings := []string{}
+	if v.Active != 0 && v.Active == v.Preview {
+		warnings = append(warnings, fmt.Sprintf("Preview and Program are both input %d", v.Active))
+	}
+	return warnings
+}
+
+// GetStatusHandler returns vMix version and loaded preset for [GET] /api/status .
+// vMix does not report its own uptime, so "uptime" is how long the host has been reachable from this server.
+func GetStatusHandler(c *gin.Context) {
+	since := vmixStatus.ConnectedSince()
+	vmix, vmixExtra := vmixSnapshot()
+	mixActive := map[string]uint{}
+	for _, m := range mixes(vmix, vmixExtra) {
+		mixActive[strconv.Itoa(int(m.Number))] = m.Active
+	}
+	status := gin.H{
+		"version":         vmix.Version,
+		"edition":         vmix.Edition,
+		"preset":          vmix.Preset,
+		"preset_name":     presetName(vmix.Preset),
+		"preset_saved":    vmix.Preset != "",
+		"mix_active":      mixActive,
+		"connected_since": nil,
+		"uptime":          0,
+		"warnings":        stateWarnings(vmix),
+	}
+	if !since.IsZero() {
+		status["connected_since"] = since
+		status["uptime"] = int(time.Since(since).Seconds())
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// PingHandler returns minimal connection state for [GET] /api/ping .
+// It does not access vMix, so it is cheap enough to poll every second.
+// lastRefreshMs is milliseconds since the last successful refresh, -1 if never refreshed.
+func PingHandler(c *gin.Context) {
+	lastRefreshMs := int64(-1)
+	if t := vmixStatus.LastRefresh(); !t.IsZero() {
+		lastRefreshMs = time.Since(t).Milliseconds()
+	}
+	vmix, _ := vmixSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"connected":     vmixStatus.LastError() == nil,
+		"vmixVersion":   vmix.Version,
+		"lastRefreshMs": lastRefreshMs,
+	})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultIdempotencyTTL is how long Idempotency-Key results are kept. Overridden by -idempotency-ttl flag.
+const defaultIdempotencyTTL = 30 * time.Second
+
+// idempotentResult is a cached response for an Idempotency-Key.
+type idempotentResult struct {
+	done    chan struct{} // closed when status and body are set
+	status  int
+	body    gin.H
+	expires time.Time
+}
+
+// idempotencyCache remembers responses by Idempotency-Key so retried requests are not sent to vMix twice.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotentResult
+}
+
+var idempotencyKeys = &idempotencyCache{
+	ttl:     defaultIdempotencyTTL,
+	entries: map[string]*idempotentResult{},
+}
+
+// do runs fn once per key within TTL. Later calls with the same key wait for and return the first result.
+// replayed reports whether the result came from cache.
+func (c *idempotencyCache) do(key string, fn func() (int, gin.H)) (status int, body gin.H, replayed bool) {
+	now := time.Now()
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if !e.expires.IsZero() && now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.status, e.body, true
+	}
+	e := &idempotentResult{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.status, e.body = fn()
+	c.mu.Lock()
+	e.expires = time.Now().Add(c.ttl)
+	c.mu.Unlock()
+	close(e.done)
+	return e.status, e.body, false
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// identify durations in milliseconds.
+const (
+	defaultIdentifyDuration = 3000
+	maxIdentifyDuration     = 30000
+)
+
+// IdentifyRequest Request JSON for IdentifyInputHandler
+type IdentifyRequest struct {
+	Method     string `json:"method"`     // "preview" or "overlay". defaults to "preview".
+	Channel    int    `json:"channel"`    // overlay channel for "overlay" method. defaults to 1.
+	DurationMs uint   `json:"durationMs"` // how long input is shown in milliseconds. defaults to 3000.
+}
+
+// Validate form and fill defaults.
+func (r *IdentifyRequest) Validate() error {
+	switch r.Method {
+	case "":
+		r.Method = "preview"
+	case "preview", "overlay":
+	default:
+		return fmt.Errorf("Unknown method %q. valid methods: preview, overlay", r.Method)
+	}
+	if r.Method == "overlay" {
+		if r.Channel == 0 {
+			r.Channel = 1
+		}
+		if r.Channel < 1 || r.Channel > maxOverlayChannel {
+			return fmt.Errorf("Overlay channel must be 1 to %d", maxOverlayChannel)
+		}
+	}
+	if r.DurationMs == 0 {
+		r.DurationMs = defaultIdentifyDuration
+	}
+	if r.DurationMs > maxIdentifyDuration {
+		return fmt.Errorf("durationMs must be %d or less", maxIdentifyDuration)
+	}
+	return nil
+}
+
+// target returns preview or overlay channel the request shows input on.
+func (r *IdentifyRequest) target() string {
+	if r.Method == "overlay" {
+		return fmt.Sprintf("overlay%d", r.Channel)
+	}
+	return r.Method
+}
+
+// identifyLocks serializes identify on the same preview or overlay channel,
+// so an overlapping identify does not read the input shown by another one as the prior input.
+var identifyLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: map[string]*sync.Mutex{}}
+
+// identifyLock returns mutex of identify target.
+func identifyLock(target string) *sync.Mutex {
+	identifyLocks.Lock()
+	defer identifyLocks.Unlock()
+	l, ok := identifyLocks.locks[target]
+	if !ok {
+		l = &sync.Mutex{}
+		identifyLocks.locks[target] = l
+	}
+	return l
+}
+
+// identifyInput shows input by method for duration, then reverts preview or overlay channel to the prior input.
+// Prior state is read from the latest refresh.
+func identifyInput(v *vmixgo.Vmix, e *vMixExtra, input vmixgo.Input, r IdentifyRequest) error {
+	duration := time.Duration(r.DurationMs) * time.Millisecond
+	if r.Method == "overlay" {
+		fn := fmt.Sprintf("OverlayInput%d", r.Channel)
+		prior := overlayInput(e, r.Channel)
+		if err := sendInputFunction(v, fn+"In", input.Key); err != nil {
+			return err
+		}
+		time.Sleep(duration)
+		if prior == 0 {
+			return sendFunction(v, fn+"Out", nil)
+		}
+		return sendInputFunction(v, fn+"In", strconv.Itoa(int(prior)))
+	}
+
+	prior := v.Preview
+	if err := PreviewInput(v, input.Key); err != nil {
+		return err
+	}
+	time.Sleep(duration)
+	if prior == 0 {
+		return nil
+	}
+	return PreviewInput(v, strconv.Itoa(int(prior)))
+}
+
+// IdentifyInputHandler briefly shows input on preview or an overlay channel for [POST] /api/inputs/:key/identify .
+// Responds after the prior state is restored. Request body is optional.
+// Identify on the same preview or overlay channel waits until the running one is restored.
+func IdentifyInputHandler(c *gin.Context) {
+	req := IdentifyRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	l := identifyLock(req.target())
+	l.Lock()
+	defer l.Unlock()
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, vmixExtra := vmixSnapshot()
+	if err := identifyInput(vmix, vmixExtra, input, req); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input":      input.Key,
+		"method":     req.Method,
+		"durationMs": req.DurationMs,
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestIdentifyInputHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/3/identify", `{"durationMs":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Function != "PreviewInput" || calls[0].Query.Get("Input") != vmix.Inputs.Input[2].Key {
+		t.Errorf("unexpected call: %v", calls[0].Query)
+	}
+	// reverted to prior preview
+	if calls[1].Function != "PreviewInput" || calls[1].Query.Get("Input") != "2" {
+		t.Errorf("unexpected revert call: %v", calls[1].Query)
+	}
+
+	// overlay channel 1 has input 3 in DefaultXML, so it is restored instead of turned off.
+	s.Reset()
+	w = doRequest(r, http.MethodPost, "/api/inputs/1/identify", `{"method":"overlay","durationMs":1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls = s.Calls()
+	if len(calls) != 2 || calls[0].Function != "OverlayInput1In" || calls[1].Function != "OverlayInput1In" || calls[1].Query.Get("Input") != "3" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+
+	s.Reset()
+	w = doRequest(r, http.MethodPost, "/api/inputs/1/identify", `{"method":"overlay","channel":2,"durationMs":1}`)
+	calls = s.Calls()
+	if w.Code != http.StatusOK || len(calls) != 2 || calls[1].Function != "OverlayInput2Out" {
+		t.Errorf("status = %d, calls = %v", w.Code, calls)
+	}
+
+	// Overlapping identify on preview waits for the running one, so each restores the original preview.
+	s.Reset()
+	var wg sync.WaitGroup
+	for _, key := range []string{"1", "3"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if w := doRequest(r, http.MethodPost, "/api/inputs/"+key+"/identify", `{"durationMs":20}`); w.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+		}(key)
+	}
+	wg.Wait()
+	calls = s.Calls()
+	if len(calls) != 4 || calls[1].Query.Get("Input") != "2" || calls[3].Query.Get("Input") != "2" {
+		t.Errorf("identify interleaved: %v", calls)
+	}
+
+	for _, body := range []string{`{"method":"flash"}`, `{"method":"overlay","channel":9}`, `{"durationMs":60000}`} {
+		if w := doRequest(r, http.MethodPost, "/api/inputs/1/identify", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := doRequest(r, http.MethodPost, "/api/inputs/missing/identify", ""); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// maxImageSize is the largest image accepted by UploadImageHandler. 10MB.
+const maxImageSize = 10 << 20
+
+// imageExtensions maps accepted image content types to file extensions.
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/bmp":  ".bmp",
+}
+
+// SetImage sets image file of image input.
+func SetImage(v *vmixgo.Vmix, input string, filename string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = filename
+	return sendFunction(v, "SetImage", params)
+}
+
+// UploadImageHandler saves uploaded "file" form image into -upload-dir and sets it to image input
+// for [POST] /api/inputs/:key/image . upload directory must be readable by vMix.
+// ?index= sets image field of title by index instead.
+func UploadImageHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	index := -1
+	if q := c.Query("index"); q != "" {
+		if err := validateSelectedIndex("SetImage", map[string]string{"Input": input.Key, "SelectedIndex": q}); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		index, _ = strconv.Atoi(q)
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImageSize+1<<20)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if fh.Size > maxImageSize {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("Image must be smaller than %d bytes", maxImageSize),
+		})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	ext, ok := imageExtensions[http.DetectContentType(b)]
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "File must be PNG, JPEG, GIF or BMP image",
+		})
+		return
+	}
+
+	if err := os.MkdirAll(*uploadDir, 0755); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	name := strings.TrimSuffix(filepath.Base(fh.Filename), filepath.Ext(fh.Filename))
+	path, err := filepath.Abs(filepath.Join(*uploadDir, fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), name, ext)))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if index >= 0 {
+		err = SetImageIndex(vmix, input.Key, index, path)
+	} else {
+		err = SetImage(vmix, input.Key, path)
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input": input.Key,
+		"path":  path,
+	})
+}
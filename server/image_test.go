@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func uploadRequest(t *testing.T, path string, filename string, content []byte) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestUploadImageHandler(t *testing.T) {
+	s, r := setupTest(t)
+	*uploadDir = t.TempDir()
+
+	img := &bytes.Buffer{}
+	png.Encode(img, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, uploadRequest(t, "/api/inputs/3/image", "fan.png", img.Bytes()))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "SetImage" {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	saved, err := ioutil.ReadFile(calls[0].Query.Get("Value"))
+	if err != nil || !bytes.Equal(saved, img.Bytes()) {
+		t.Errorf("image not saved to %s: %v", calls[0].Query.Get("Value"), err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, uploadRequest(t, "/api/inputs/3/image", "evil.png", []byte("#!/bin/sh\n")))
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+}
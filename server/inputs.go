@@ -0,0 +1,467 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// inputResponse is vmixgo.Input with fields resolved by vmix-utility.
+type inputResponse struct {
+	vmixgo.Input
+	Category string   `json:"category"` // coarse category of SceneType. see inputCategory.
+	Outputs  []string `json:"outputs"`  // outputs which input currently feeds. e.g. ["Program","Fullscreen"] .
+}
+
+// inputCategories maps vMix input types to coarse categories for UI.
+var inputCategories = map[string]string{
+	"Capture":        "camera",
+	"NDI":            "camera",
+	"Stream":         "camera",
+	"SRT":            "camera",
+	"VideoCall":      "camera",
+	"Zoom":           "camera",
+	"DesktopCapture": "camera",
+	"Video":          "video",
+	"VideoList":      "video",
+	"VideoDelay":     "video",
+	"Replay":         "video",
+	"Image":          "image",
+	"Photos":         "image",
+	"Colour":         "image",
+	"Blank":          "image",
+	"PowerPoint":     "image",
+	"GT":             "title",
+	"Xaml":           "title",
+	"Title":          "title",
+	"Audio":          "audio",
+	"AudioFile":      "audio",
+	"Browser":        "browser",
+}
+
+// inputCategory returns category of vMix input type. unknown types are "other".
+func inputCategory(sceneType string) string {
+	if c, ok := inputCategories[sceneType]; ok {
+		return c
+	}
+	return "other"
+}
+
+// inputsResponse returns current vmix inputs with resolved outputs.
+func inputsResponse() []inputResponse {
+	vmix, vmixExtra := vmixSnapshot()
+	states := outputStates(vmix, vmixExtra)
+	inputs := make([]inputResponse, 0, len(vmix.Inputs.Input))
+	for _, input := range vmix.Inputs.Input {
+		inputs = append(inputs, inputResponse{
+			Input:    input,
+			Category: inputCategory(input.SceneType),
+			Outputs:  inputOutputs(vmix, vmixExtra, states, input),
+		})
+	}
+	return inputs
+}
+
+// BulkInputsRequest Request JSON for BulkInputsHandler
+type BulkInputsRequest struct {
+	Type     string          `json:"type"`     // SceneType filter. e.g. "Capture" . empty matches any type.
+	State    string          `json:"state"`    // State filter. e.g. "Running" . empty matches any state.
+	Function string          `json:"function"` // function name. e.g. "AudioOff" .
+	Queries  []FunctionQuery `json:"queries"`  // Key-Value queries. Input is set for each input.
+	Confirm  bool            `json:"confirm"`  // required for destructiveFunctions.
+}
+
+// Validate form
+func (r *BulkInputsRequest) Validate() error {
+	if strings.TrimSpace(r.Function) == "" {
+		return fmt.Errorf("Function empty")
+	}
+	for _, v := range r.Queries {
+		if v.Key == "" || v.Value == "" {
+			return fmt.Errorf("Invalid queries")
+		}
+		if v.Key == "Input" {
+			return fmt.Errorf("Input query is set by filter")
+		}
+	}
+	return nil
+}
+
+// Match reports whether input matches type and state filter.
+func (r *BulkInputsRequest) Match(input vmixgo.Input) bool {
+	if r.Type != "" && !strings.EqualFold(r.Type, input.SceneType) {
+		return false
+	}
+	if r.State != "" && !strings.EqualFold(r.State, input.State) {
+		return false
+	}
+	return true
+}
+
+// InputResult is a result of function sent to a single input.
+type InputResult struct {
+	Input string `json:"input"`           // input key.
+	Error string `json:"error,omitempty"` // error message. empty on success.
+}
+
+// sendToInputs sends function to each input concurrently, bounded by maxConcurrentFunctions.
+// Input query is set to each input key. status is 200 OK, or 202 Accepted if any function failed.
+func sendToInputs(inputs []vmixgo.Input, function string, params map[string]string) (results []InputResult, status int) {
+	results = make([]InputResult, len(inputs))
+	wg := &sync.WaitGroup{}
+	sem := make(chan struct{}, maxConcurrentFunctions)
+	vmix, _ := vmixSnapshot()
+	for i, input := range inputs {
+		p := make(map[string]string, len(params)+1)
+		for k, v := range params {
+			p[k] = v
+		}
+		p["Input"] = input.Key
+		results[i].Input = input.Key
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem }()
+			defer wg.Done()
+			if err := sendFunction(vmix, function, p); err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	status = http.StatusOK
+	for _, r := range results {
+		if r.Error != "" {
+			status = http.StatusAccepted
+			break
+		}
+	}
+	return results, status
+}
+
+// BulkInputsHandler sends function to every input matching filter for [POST] /api/inputs/bulk.
+// Every input is validated before any function is sent.
+func BulkInputsHandler(c *gin.Context) {
+	req := BulkInputsRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if isDestructive(req.Function) && !req.Confirm {
+		c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{
+			"error": fmt.Sprintf("%s is destructive. Send again with \"confirm\": true", req.Function),
+		})
+		return
+	}
+
+	params := make(map[string]string)
+	for _, v := range req.Queries {
+		params[v.Key] = v.Value
+	}
+	inputs := []vmixgo.Input{}
+	vmix, _ := vmixSnapshot()
+	for _, input := range vmix.Inputs.Input {
+		if !req.Match(input) {
+			continue
+		}
+		p := map[string]string{"Input": input.Key}
+		for k, v := range params {
+			p[k] = v
+		}
+		if err := validateSelectedIndex(req.Function, p); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		inputs = append(inputs, input)
+	}
+	results, status := sendToInputs(inputs, req.Function, params)
+	c.JSON(status, gin.H{
+		"results": results,
+	})
+}
+
+// TakeRequest Request JSON for TakeInputHandler
+type TakeRequest struct {
+	Mode       string `json:"mode"`       // "cut", "preview" or "fade".
+	DurationMs uint   `json:"durationMs"` // fade duration in milliseconds. defaults to 500.
+}
+
+// TakeInputHandler cuts, previews or fades input for [POST] /api/inputs/:key/take .
+func TakeInputHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := TakeRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	params := make(map[string]string)
+	params["Input"] = input.Key
+	var function string
+	switch req.Mode {
+	case "cut":
+		function = "Cut"
+	case "preview":
+		function = "PreviewInput"
+	case "fade":
+		function = "Fade"
+		duration := req.DurationMs
+		if duration == 0 {
+			duration = defaultTransitionDuration
+		}
+		params["Duration"] = strconv.Itoa(int(duration))
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unknown mode %q. valid modes: cut, preview, fade", req.Mode),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := sendFunction(vmix, function, params); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input": input.Key,
+		"mode":  req.Mode,
+	})
+}
+
+// RemoveInput removes input from vMix.
+func RemoveInput(v *vmixgo.Vmix, input string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	return sendFunction(v, "RemoveInput", params)
+}
+
+// removeInput removes input and returns renumbered inputs.
+func removeInput(c *gin.Context, input vmixgo.Input) {
+	vmix, _ := vmixSnapshot()
+	if err := RemoveInput(vmix, input.Key); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"removed": input.Key,
+		"inputs":  inputsResponse(),
+	})
+}
+
+// DeleteInputHandler removes input by key for [DELETE] /api/inputs/:key .
+func DeleteInputHandler(c *gin.Context) {
+	key := c.Param("key")
+	vmix, _ := vmixSnapshot()
+	for _, input := range vmix.Inputs.Input {
+		if input.Key == key {
+			removeInput(c, input)
+			return
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+		"error": "Input not found",
+	})
+}
+
+// DeleteInputByNumberHandler removes input by number for [DELETE] /api/inputs/number/:n .
+// Numbers shift when inputs are removed, so the number is resolved to key on fresh XML right before removing.
+func DeleteInputByNumberHandler(c *gin.Context) {
+	n, err := strconv.ParseUint(c.Param("n"), 10, 32)
+	if err != nil || n == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid input number : " + c.Param("n"),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	for _, input := range vmix.Inputs.Input {
+		if input.Number == uint(n) {
+			removeInput(c, input)
+			return
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+		"error": fmt.Sprintf("Input %d not found", n),
+	})
+}
+
+// VolatileInput is input state which changes constantly, for high-frequency polling.
+type VolatileInput struct {
+	Key      string     `json:"key"`
+	State    string     `json:"state"`    // e.g. "Running", "Paused" .
+	Position int        `json:"position"` // playback position in milliseconds.
+	Meters   [2]float64 `json:"meters"`   // audio meters F1 and F2.
+}
+
+// GetVolatileInputsHandler returns only volatile state of every input for [GET] /api/inputs/volatile .
+// It serves the latest state polled by -refresh-interval and never refreshes vMix itself,
+// so high-frequency clients do not multiply requests to vMix.
+func GetVolatileInputsHandler(c *gin.Context) {
+	if *refreshInterval <= 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Background refresh disabled. Start with -refresh-interval to poll volatile state",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	inputs := make([]VolatileInput, 0, len(vmix.Inputs.Input))
+	for _, input := range vmix.Inputs.Input {
+		inputs = append(inputs, VolatileInput{
+			Key:      input.Key,
+			State:    input.State,
+			Position: input.AttrPosition,
+			Meters:   [2]float64{input.MeterF1, input.MeterF2},
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"inputs": inputs,
+	})
+}
+
+// SetInputName sets title of input.
+func SetInputName(v *vmixgo.Vmix, input string, title string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = title
+	return sendFunction(v, "SetInputName", params)
+}
+
+// SetShortTitle sets short title of input, which is shown in multiview labels.
+func SetShortTitle(v *vmixgo.Vmix, input string, shortTitle string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = shortTitle
+	return sendFunction(v, "SetShortTitle", params)
+}
+
+// uniqueInputNames rejects renaming input to title of another input, which breaks selecting inputs by title.
+// Set by -unique-input-names flag.
+var uniqueInputNames = true
+
+// titleConflict returns another input which already has title.
+func titleConflict(v *vmixgo.Vmix, key string, title string) (vmixgo.Input, bool) {
+	for _, in := range v.Inputs.Input {
+		if in.Key != key && in.Title == title {
+			return in, true
+		}
+	}
+	return vmixgo.Input{}, false
+}
+
+// RenameInputRequest Request JSON for RenameInputHandler
+type RenameInputRequest struct {
+	Title      *string `json:"title"`      // optional new title.
+	ShortTitle *string `json:"shortTitle"` // optional new short title.
+}
+
+// RenameInputHandler sets title and short title of input for [POST] /api/inputs/:key/name
+// and returns the resulting names read back from vMix.
+// Title already used by another input is rejected with 409 unless -unique-input-names=false .
+// vMix is refreshed before the lookup, so renames and inputs added in vMix since the last refresh are checked.
+func RenameInputHandler(c *gin.Context) {
+	req := RenameInputRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if req.Title == nil && req.ShortTitle == nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "title or shortTitle required",
+		})
+		return
+	}
+	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "title must not be empty",
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if req.Title != nil && uniqueInputNames {
+		if conflict, ok := titleConflict(vmix, input.Key, *req.Title); ok {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error":    fmt.Sprintf("Input %d already has title %q", conflict.Number, conflict.Title),
+				"conflict": conflict.Key,
+			})
+			return
+		}
+	}
+	var err error
+	if req.Title != nil {
+		err = SetInputName(vmix, input.Key, *req.Title)
+	}
+	if err == nil && req.ShortTitle != nil {
+		err = SetShortTitle(vmix, input.Key, *req.ShortTitle)
+	}
+	if err == nil {
+		err = refreshvMix()
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	renamed, _ := findInput(input.Key)
+	c.JSON(http.StatusOK, gin.H{
+		"input":      input.Key,
+		"title":      renamed.Title,
+		"shortTitle": renamed.ShortTitle,
+	})
+}
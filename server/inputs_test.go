@@ -0,0 +1,196 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestBulkInputsHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/bulk", `{"state":"paused","function":"AudioOff"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	for _, c := range calls {
+		if c.Function != "AudioOff" || c.Query.Get("Input") == vmix.Inputs.Input[0].Key {
+			t.Errorf("unexpected call: %v", c.Query)
+		}
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/inputs/bulk", `{"type":"Video","function":"Play","queries":[{"key":"Input","value":"1"}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	s.Reset()
+	w = doRequest(r, http.MethodPost, "/api/inputs/bulk", `{"function":"RemoveInput"}`)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("destructive without confirm: status = %d, want %d", w.Code, http.StatusPreconditionRequired)
+	}
+	w = doRequest(r, http.MethodPost, "/api/inputs/bulk", `{"type":"GT","function":"SetText","queries":[{"key":"SelectedIndex","value":"5"},{"key":"Value","value":"x"}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("SelectedIndex out of range: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+	w = doRequest(r, http.MethodPost, "/api/inputs/bulk", `{"type":"Video","function":"RemoveInput","confirm":true}`)
+	if w.Code != http.StatusOK || len(s.Calls()) != 1 {
+		t.Errorf("destructive with confirm: status = %d, calls = %d", w.Code, len(s.Calls()))
+	}
+}
+
+func TestInputCategory(t *testing.T) {
+	for sceneType, want := range map[string]string{
+		"Capture": "camera",
+		"Video":   "video",
+		"GT":      "title",
+		"Unknown": "other",
+		"":        "other",
+	} {
+		if got := inputCategory(sceneType); got != want {
+			t.Errorf("inputCategory(%q) = %q, want %q", sceneType, got, want)
+		}
+	}
+}
+
+func TestTakeInputHandler(t *testing.T) {
+	s, r := setupTest(t)
+	for mode, function := range map[string]string{"cut": "Cut", "preview": "PreviewInput", "fade": "Fade"} {
+		s.Reset()
+		w := doRequest(r, http.MethodPost, "/api/inputs/2/take", `{"mode":"`+mode+`"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d: %s", mode, w.Code, http.StatusOK, w.Body.String())
+		}
+		calls := s.Calls()
+		if len(calls) != 1 || calls[0].Function != function || calls[0].Query.Get("Input") != vmix.Inputs.Input[1].Key {
+			t.Errorf("%s: unexpected calls: %+v", mode, calls)
+		}
+	}
+
+	w := doRequest(r, http.MethodPost, "/api/inputs/2/take", `{"mode":"wipe"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDeleteInputByNumberHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	// Input 2 was removed in vMix after last refresh, so number 2 is now "Lower Third.gtzip" .
+	shifted := regexp.MustCompile(`<input key="[^"]+" number="2".*</input>\n`).ReplaceAllString(vmixtest.DefaultXML, "")
+	shifted = strings.Replace(shifted, `number="3" type="GT"`, `number="2" type="GT"`, 1)
+	s.SetXML(shifted)
+
+	w := doRequest(r, http.MethodDelete, "/api/inputs/number/2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "RemoveInput" || calls[0].Query.Get("Input") != "0b1a9c6e-3333-4c7a-9a53-6f2b4d1e0003" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	for path, want := range map[string]int{
+		"/api/inputs/number/9":    http.StatusNotFound,
+		"/api/inputs/number/zero": http.StatusBadRequest,
+		"/api/inputs/2":           http.StatusNotFound,
+	} {
+		if w := doRequest(r, http.MethodDelete, path, ""); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestGetVolatileInputsHandler(t *testing.T) {
+	s, r := setupTest(t)
+	if w := doRequest(r, http.MethodGet, "/api/inputs/volatile", ""); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d without -refresh-interval, want %d", w.Code, http.StatusNotFound)
+	}
+
+	defer func(interval time.Duration) { *refreshInterval = interval }(*refreshInterval)
+	*refreshInterval = time.Second
+	// Served from the last refresh even if vMix fails now.
+	s.SetXMLResponse(http.StatusInternalServerError, "")
+	w := doRequest(r, http.MethodGet, "/api/inputs/volatile", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	want := `{"key":"` + vmix.Inputs.Input[0].Key + `","state":"Running","position":0,"meters":[0.1,0.1]}`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("response does not contain %s: %s", want, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "title") {
+		t.Errorf("response contains non-volatile fields: %s", w.Body.String())
+	}
+}
+
+func TestRenameInputHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/1/name", `{"title":"Wide","shortTitle":"W"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Function != "SetInputName" || calls[0].Query.Get("Value") != "Wide" {
+		t.Errorf("unexpected call: %v", calls[0].Query)
+	}
+	if calls[1].Function != "SetShortTitle" || calls[1].Query.Get("Value") != "W" {
+		t.Errorf("unexpected call: %v", calls[1].Query)
+	}
+	// fake vMix does not apply the functions, so current names are returned.
+	if !strings.Contains(w.Body.String(), `"shortTitle":"CAM 1"`) {
+		t.Errorf("short title not returned: %s", w.Body.String())
+	}
+
+	s.Reset()
+	for _, body := range []string{`{}`, `{"title":""}`} {
+		if w := doRequest(r, http.MethodPost, "/api/inputs/1/name", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+
+	// Title of another input conflicts. Renaming input to its own title does not.
+	w = doRequest(r, http.MethodPost, "/api/inputs/1/name", `{"title":"opener.mp4"}`)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), `"conflict":"`+vmix.Inputs.Input[1].Key+`"`) {
+		t.Errorf("conflict: %d %s", w.Code, w.Body.String())
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite conflict")
+	}
+	if w := doRequest(r, http.MethodPost, "/api/inputs/1/name", `{"title":"CAM 1"}`); w.Code != http.StatusOK {
+		t.Errorf("own title: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Title changed in vMix since the last refresh is checked.
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, `title="opener.mp4"`, `title="Intro"`, 1))
+	s.Reset()
+	if w := doRequest(r, http.MethodPost, "/api/inputs/1/name", `{"title":"Intro"}`); w.Code != http.StatusConflict {
+		t.Errorf("title renamed in vMix: status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite conflict")
+	}
+	s.SetXML(vmixtest.DefaultXML)
+
+	uniqueInputNames = false
+	defer func() { uniqueInputNames = true }()
+	if w := doRequest(r, http.MethodPost, "/api/inputs/1/name", `{"title":"opener.mp4"}`); w.Code != http.StatusOK {
+		t.Errorf("enforcement disabled: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// selectedIndexFunctions are vMix functions accepting SelectedIndex to target a title field or layer by index
+// instead of SelectedName. Index starts from 0 and counts text and image fields of the title in order.
+var selectedIndexFunctions = map[string]bool{
+	"SetText":            true,
+	"SetTextColour":      true,
+	"SetTextVisible":     true,
+	"SetTextVisibleOn":   true,
+	"SetTextVisibleOff":  true,
+	"SetImage":           true,
+	"SetImageVisible":    true,
+	"SetImageVisibleOn":  true,
+	"SetImageVisibleOff": true,
+	"SetColor":           true,
+	"SetCountdown":       true,
+	"StartCountdown":     true,
+	"StopCountdown":      true,
+	"PauseCountdown":     true,
+	"AdjustCountdown":    true,
+}
+
+// validateSelectedIndex validates SelectedIndex param of funcname if exists.
+// If target input is known, index must be less than the number of its fields.
+func validateSelectedIndex(funcname string, params map[string]string) error {
+	value, ok := params["SelectedIndex"]
+	if !ok {
+		return nil
+	}
+	if !selectedIndexFunctions[funcname] {
+		return fmt.Errorf("%s does not accept SelectedIndex", funcname)
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return fmt.Errorf("SelectedIndex must be a non-negative integer")
+	}
+	input, ok := findInput(params["Input"])
+	if !ok {
+		return nil
+	}
+	_, vmixExtra := vmixSnapshot()
+	if e, ok := vmixExtra.input(input.Key); ok && len(e.Fields()) > 0 && index >= len(e.Fields()) {
+		return fmt.Errorf("SelectedIndex %d out of range. %s has %d fields", index, input.Title, len(e.Fields()))
+	}
+	return nil
+}
+
+// SetTextIndex sets text of title field by index.
+func SetTextIndex(v *vmixgo.Vmix, input string, index int, value string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["SelectedIndex"] = strconv.Itoa(index)
+	params["Value"] = value
+	return sendFunction(v, "SetText", params)
+}
+
+// SetImageIndex sets image file of title image field by index.
+func SetImageIndex(v *vmixgo.Vmix, input string, index int, filename string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["SelectedIndex"] = strconv.Itoa(index)
+	params["Value"] = filename
+	return sendFunction(v, "SetImage", params)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestValidateSelectedIndex(t *testing.T) {
+	setupTest(t)
+
+	for _, tt := range []struct {
+		funcname string
+		params   map[string]string
+		err      bool
+	}{
+		{"Cut", map[string]string{}, false},
+		{"SetText", map[string]string{"Input": "3", "SelectedIndex": "0"}, false},
+		{"SetText", map[string]string{"Input": "3", "SelectedIndex": "1"}, true},
+		{"SetText", map[string]string{"Input": "3", "SelectedIndex": "-1"}, true},
+		{"SetText", map[string]string{"Input": "3", "SelectedIndex": "first"}, true},
+		{"SetText", map[string]string{"Input": "1", "SelectedIndex": "5"}, false},
+		{"Cut", map[string]string{"SelectedIndex": "0"}, true},
+	} {
+		if err := validateSelectedIndex(tt.funcname, tt.params); (err != nil) != tt.err {
+			t.Errorf("%s %v: err = %v", tt.funcname, tt.params, err)
+		}
+	}
+}
+
+func TestSetFieldsHandlerIndex(t *testing.T) {
+	s, r := setupTest(t)
+
+	w := doRequest(r, http.MethodPost, "/api/inputs/3/fields", `{"fields":[{"index":0,"value":" again","mode":"append"}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Query.Get("SelectedIndex") != "0" || calls[0].Query.Get("Value") != "Hello again" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	if w := doRequest(r, http.MethodPost, "/api/inputs/3/fields", `{"fields":[{"index":3,"value":"x"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut","queries":[{"key":"SelectedIndex","value":"0"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
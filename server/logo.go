@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// SetLogo sets logo image file stamped on input. filename must be readable by vMix.
+func SetLogo(v *vmixgo.Vmix, input string, filename string) error {
+	if strings.TrimSpace(filename) == "" {
+		return fmt.Errorf("Logo filename empty")
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = filename
+	return sendFunction(v, "SetLogo", params)
+}
+
+// ClearLogo removes logo from input.
+func ClearLogo(v *vmixgo.Vmix, input string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = ""
+	return sendFunction(v, "SetLogo", params)
+}
+
+// LogoRequest Request JSON for SetLogoHandler
+type LogoRequest struct {
+	Filename string `json:"filename"` // logo image path on vMix host. e.g. "C:\\logos\\sponsor.png" .
+}
+
+// SetLogoHandler sets logo of input for [POST] /api/inputs/:key/logo .
+func SetLogoHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := LogoRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if strings.TrimSpace(req.Filename) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Logo filename empty",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := SetLogo(vmix, input.Key, req.Filename); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"input":    input.Key,
+		"filename": req.Filename,
+	})
+}
+
+// ClearLogoHandler removes logo of input for [DELETE] /api/inputs/:key/logo .
+func ClearLogoHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := ClearLogo(vmix, input.Key); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
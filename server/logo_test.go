@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLogoHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/1/logo", `{"filename":"C:\\logos\\sponsor.png"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "SetLogo" || calls[0].Query.Get("Value") != `C:\logos\sponsor.png` || calls[0].Query.Get("Input") != vmix.Inputs.Input[0].Key {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	s.Reset()
+	if w := doRequest(r, http.MethodPost, "/api/inputs/1/logo", `{"filename":" "}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := doRequest(r, http.MethodPost, "/api/inputs/missing/logo", `{"filename":"a.png"}`); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+
+	w = doRequest(r, http.MethodDelete, "/api/inputs/1/logo", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "SetLogo" || calls[0].Query.Get("Value") != "" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -25,10 +29,31 @@ type vMixFunction struct {
 
 // vMix variables
 var (
-	hostaddr      *string        // API Listen host
-	vmixaddr      *string        // Target vMix host address
-	vMixFunctions []vMixFunction // vMix functions slice. TODO!
-	vmix          *vmixgo.Vmix
+	hostaddr        *string        // API Listen host
+	basePath        *string        // path prefix of every route. e.g. "/vmix"
+	vmixaddr        *string        // Target vMix host address
+	vmixPort        *int           // Target vMix port used if vmixaddr has no port
+	token           *string        // API access token. empty disables authentication
+	errorMarkers    *string        // comma separated functionErrorMarkers
+	destructive     *string        // comma separated destructiveFunctions
+	maxIdleConns    *int           // idle connections kept to vMix
+	idempotentTTL   *time.Duration // Idempotency-Key cache TTL
+	coalesceWindow  *time.Duration // window to merge identical function sends. 0 disables
+	uploadDir       *string        // directory to save uploaded images
+	thumbnailTTL    *time.Duration // input thumbnail cache TTL
+	refreshInterval *time.Duration // background refresh interval. 0 disables
+	playbackEnd     *time.Duration // remaining time which fires playback_ending
+	openBrowser     *bool          // open browser on startup
+	gzipEnabled     *bool          // gzip compression of /api responses
+	debug           *bool          // keep raw XML of last two refreshes for /api/debug/xml
+	uniqueNames     *bool          // reject renaming input to title of another input
+	noBrowser       *bool          // shortcut for -open-browser=false
+	idleTimeout     *time.Duration // idle connection timeout to vMix
+	functionTimeout *time.Duration // timeout of functions without per-function timeout
+	onConnectScript *string        // vMix script started when vMix becomes reachable
+	onReconnect     *bool          // start onConnectScript after reconnects too
+	vMixFunctions   []vMixFunction // vMix functions slice. TODO!
+	vmix            *vmixgo.Vmix
 )
 
 // Static files
@@ -47,21 +72,22 @@ func GetvMixURLHandler(c *gin.Context) {
 
 // RefreshInputHandler returns vMix API Endpoint.
 func RefreshInputHandler(c *gin.Context) {
-	var err error
-	vmix, err = vmix.Refresh()
-	if err != nil {
+	if err := refreshvMix(); err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"err": err.Error(),
 		})
 		return
 	}
+	vmix, _ := vmixSnapshot()
 	c.JSON(http.StatusOK, gin.H{
-		"inputs": vmix.Inputs.Input,
+		"inputs":   inputsResponse(),
+		"warnings": stateWarnings(vmix),
 	})
 }
 
 // GetInputsHandler returns available vmix inputs for [GET] /api/inputs as JSON.
 func GetInputsHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
 	if vmix == nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"error": "vmix instance not loaded",
@@ -75,27 +101,44 @@ func GetInputsHandler(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"inputs": vmix.Inputs.Input,
+		"inputs": inputsResponse(),
 	})
 	return
 }
 
 // GetFunctionsHandler returns available functions/value/input combinations for [GET] /api/functions as JSON.
+// Functions are sorted by usage, or by name with ?sort=alphabetical .
 func GetFunctionsHandler(c *gin.Context) {
+	order := c.DefaultQuery("sort", "usage")
+	if order != "usage" && order != "alphabetical" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "sort must be usage or alphabetical",
+		})
+		return
+	}
+	usage := functionUsage.get()
 	c.JSON(http.StatusOK, gin.H{
-		"functions": vMixFunctions,
+		"functions": sortFunctions(vMixFunctions, usage, order),
+		"usage":     usage,
 	})
 	return
 }
 
+// maxConcurrentFunctions limits functions sent to vMix at once by a single request.
+const maxConcurrentFunctions = 8
+
+// FunctionQuery is a Key-Value query for vMix function.
+type FunctionQuery struct {
+	Key   string `json:"key"`   // Key.
+	Value string `json:"value"` // Value.
+}
+
 // DoMultipleFunctionsRequest Request JSON for DoMultipleFunctionsHandler
 type DoMultipleFunctionsRequest struct {
-	Function string `json:"function"` // function name. e.g. "Fade" .
-	Queries  []struct {
-		Key   string `json:"key"`   // Key.
-		Value string `json:"value"` // Value.
-	} `json:"queries"` // Key-Value queries.
-	Num int `json:"num"`
+	Function string          `json:"function"` // function name. e.g. "Fade" .
+	Queries  []FunctionQuery `json:"queries"`  // Key-Value queries.
+	Num      int             `json:"num"`
+	Confirm  bool            `json:"confirm"` // required for destructiveFunctions.
 }
 
 // Validate form
@@ -111,15 +154,32 @@ func (r *DoMultipleFunctionsRequest) Validate() error {
 	if r.Num <= 0 {
 		return fmt.Errorf("Invalid Number length")
 	}
-	return nil
+	params := make(map[string]string)
+	for _, v := range r.Queries {
+		params[v.Key] = v.Value
+	}
+	return validateSelectedIndex(r.Function, params)
 }
 
 // DoMultipleFunctionsHandler Sends multiple functions to vMix.
 func DoMultipleFunctionsHandler(c *gin.Context) {
 	req := DoMultipleFunctionsRequest{}
-	c.BindJSON(&req)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
 	if err := req.Validate(); err != nil {
-		c.AbortWithError(http.StatusBadRequest, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if isDestructive(req.Function) && !req.Confirm {
+		c.AbortWithStatusJSON(http.StatusPreconditionRequired, gin.H{
+			"error": fmt.Sprintf("%s is destructive. Send again with \"confirm\": true", req.Function),
+		})
 		return
 	}
 	params := make(map[string]string)
@@ -128,15 +188,19 @@ func DoMultipleFunctionsHandler(c *gin.Context) {
 	}
 
 	wg := &sync.WaitGroup{}
-	numerrors := 0
+	sem := make(chan struct{}, maxConcurrentFunctions)
+	var numerrors int32
+	vmix, _ := vmixSnapshot()
 	for i := 0; i < req.Num; i++ {
 		wg.Add(1)
+		sem <- struct{}{}
 		go func() {
-			if err := vmix.SendFunction(req.Function, params); err != nil {
-				numerrors++
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := sendFunction(vmix, req.Function, params); err != nil {
+				atomic.AddInt32(&numerrors, 1)
 				log.Printf("Error sending function %s with %v queries. ERR : %v\n", req.Function, params, err)
 			}
-			wg.Done()
 		}()
 	}
 	wg.Wait()
@@ -149,19 +213,153 @@ func DoMultipleFunctionsHandler(c *gin.Context) {
 
 func init() {
 	vmixaddr = flag.String("vmix", "http://localhost:8088", "vMix API Address")
+	vmixPort = flag.Int("vmix-port", 0, "vMix API port used if -vmix has no port. Port in -vmix takes precedence")
 	hostaddr = flag.String("host", ":8080", "Server listen port")
-	flag.Parse()
+	basePath = flag.String("base-path", "", "Path prefix of every route to serve behind a reverse proxy subpath. e.g. /vmix")
+	token = flag.String("token", "", "Token required for /api requests. Empty disables authentication")
+	maxIdleConns = flag.Int("vmix-max-idle-conns", defaultvMixMaxIdleConns, "Idle keep-alive connections kept to vMix")
+	idleTimeout = flag.Duration("vmix-idle-timeout", defaultvMixIdleTimeout, "How long idle keep-alive connections to vMix are kept")
+	functionTimeout = flag.Duration("function-timeout", defaultFunctionTimeout, "Timeout of vMix functions. Known slow functions such as OpenPreset have longer timeouts")
+	idempotentTTL = flag.Duration("idempotency-ttl", defaultIdempotencyTTL, "How long results of requests with Idempotency-Key header are kept")
+	coalesceWindow = flag.Duration("coalesce-window", 0, "Identical functions sent to /api/function within this window are merged into a single vMix call. 0 disables")
+	uniqueNames = flag.Bool("unique-input-names", true, "Reject renaming input to a title another input already has")
+	debug = flag.Bool("debug", false, "Keep raw vMix XML of the last two refreshes for /api/debug/xml")
+	gzipEnabled = flag.Bool("gzip", true, "Compress /api responses with gzip if client accepts it")
+	refreshInterval = flag.Duration("refresh-interval", 0, "Refresh vMix in background every interval so webhook events fire without API requests. Required by /api/inputs/volatile. 0 disables")
+	playbackEnd = flag.Duration("playback-end-threshold", playbackEndThreshold, "Remaining time of playing video which fires playback_ending webhook event")
+	thumbnailTTL = flag.Duration("thumbnail-ttl", defaultThumbnailTTL, "How long input thumbnails are cached while input looks unchanged")
+	openBrowser = flag.Bool("open-browser", runtime.GOOS == "windows", "Open browser on startup. Skipped if stdout is not a terminal")
+	noBrowser = flag.Bool("no-browser", false, "Do not open browser on startup. Same as -open-browser=false")
+	uploadDir = flag.String("upload-dir", filepath.Join(os.TempDir(), "vmix-utility"), "Directory to save uploaded images. Must be readable by vMix")
+	destructive = flag.String("destructive-functions", destructiveFunctionNames(), "Comma separated functions which require \"confirm\": true on /api/function, /api/multiple and /api/inputs/bulk")
+	onConnectScript = flag.String("on-connect", "", "vMix script started after the first successful refresh. Empty disables")
+	onReconnect = flag.Bool("on-connect-reconnect", false, "Start -on-connect script after every reconnect too")
+	errorMarkers = flag.String("error-markers", strings.Join(functionErrorMarkers, ","), "Comma separated texts in vMix function response which mean the function failed")
+}
+
+// registerAPI registers handlers on /api group.
+func registerAPI(api *gin.RouterGroup) {
+	api.GET("/vmix", GetvMixURLHandler)
+	api.GET("/health", GetHealthHandler)
+	api.GET("/status", GetStatusHandler)
+	api.GET("/ping", PingHandler)
+	api.GET("/inputs", GetInputsHandler)
+	api.GET("/inputs/volatile", GetVolatileInputsHandler)
+	api.GET("/functions", GetFunctionsHandler)
+	api.DELETE("/functions/usage", ResetUsageHandler)
+	api.GET("/metrics/functions", GetFunctionMetricsHandler)
+	api.GET("/debug/xml", GetDebugXMLHandler)
+	api.POST("/activators/refresh", ActivatorRefreshHandler)
+	api.DELETE("/metrics/functions", ResetFunctionMetricsHandler)
+	api.GET("/discover", DiscoverHandler)
+	api.POST("/refresh", RefreshInputHandler)
+	api.POST("/function", DoFunctionHandler)
+	api.POST("/multiple", DoMultipleFunctionsHandler)
+	api.GET("/function/export", ExportFunctionHandler)
+	api.POST("/inputs/bulk", BulkInputsHandler)
+	api.DELETE("/inputs/:key", DeleteInputHandler)
+	api.DELETE("/inputs/number/:n", DeleteInputByNumberHandler)
+	api.POST("/inputs/:key/name", RenameInputHandler)
+	api.POST("/inputs/:key/fields", SetFieldsHandler)
+	api.POST("/inputs/:key/visibility", SetVisibilityHandler)
+	api.POST("/inputs/:key/crop", SetCropHandler)
+	api.POST("/inputs/:key/colour", SetColourHandler)
+	api.POST("/inputs/:key/colour-field", SetColourFieldHandler)
+	api.POST("/inputs/:key/channelmatrix", SetChannelMatrixHandler)
+	api.GET("/inputs/:key/properties", GetInputPropertiesHandler)
+	api.POST("/inputs/:key/image", UploadImageHandler)
+	api.POST("/inputs/:key/logo", SetLogoHandler)
+	api.DELETE("/inputs/:key/logo", ClearLogoHandler)
+	api.GET("/inputs/:key/thumbnail", GetThumbnailHandler)
+	api.POST("/inputs/:key/restart-play", RestartPlayHandler)
+	api.POST("/inputs/:key/take", TakeInputHandler)
+	api.POST("/inputs/:key/identify", IdentifyInputHandler)
+	api.POST("/transition", TransitionHandler)
+	api.GET("/transition/next", GetNextTransitionHandler)
+	api.POST("/transition/stinger/:n", StingerHandler)
+	api.GET("/transition/progress", GetTransitionProgressHandler)
+	api.GET("/transitions/effects", GetTransitionEffectsHandler)
+	api.POST("/transitions/template", TransitionTemplateHandler)
+	api.POST("/preview/next", PreviewNextHandler)
+	api.POST("/preview/previous", PreviewPreviousHandler)
+	api.POST("/switcher", SwitcherHandler)
+	api.POST("/snapshot", SnapshotHandler)
+	api.GET("/mixes", GetMixesHandler)
+	api.POST("/mix/:n/active", SetMixActiveHandler)
+	api.GET("/fader", GetFaderHandler)
+	api.POST("/fader", SetFaderHandler)
+	api.POST("/route", RouteHandler)
+	api.GET("/outputs", GetOutputsHandler)
+	api.GET("/tally", GetTallyHandler)
+	api.POST("/playback/all/pause", PauseAllHandler)
+	api.POST("/playback/all/play", PlayAllHandler)
+	api.POST("/overlays/:channel", SetOverlayHandler)
+	api.GET("/overlays/presets", GetOverlayPresetHandler)
+	api.POST("/overlays/presets", RecallOverlayPresetHandler)
+	api.PUT("/overlays/presets/:name", SaveOverlayPresetHandler)
+	// /overlays/preset is the path first published for reading and recalling layouts, kept as an alias.
+	api.GET("/overlays/preset", GetOverlayPresetHandler)
+	api.POST("/overlays/preset", RecallOverlayPresetHandler)
+	api.POST("/audio/balance", SetBalanceHandler)
+	api.POST("/audio/volume/adjust", AdjustVolumeHandler)
+	api.POST("/audio/volume/bulk", BulkVolumeHandler)
+	api.GET("/audio/snapshots", GetAudioSnapshotsHandler)
+	api.POST("/audio/snapshots", SaveAudioSnapshotHandler)
+	api.GET("/audio/snapshots/:name", GetAudioSnapshotHandler)
+	api.DELETE("/audio/snapshots/:name", DeleteAudioSnapshotHandler)
+	api.POST("/audio/snapshots/:name/recall", RecallAudioSnapshotHandler)
+	api.POST("/audio/crossfade", CrossfadeHandler)
+	api.GET("/audio/headphones", GetHeadphonesHandler)
+	api.POST("/audio/headphones", SetHeadphonesHandler)
+	api.POST("/audio/normalize", NormalizeHandler)
+	api.GET("/audio/meters", GetMetersHandler)
+	api.GET("/audio/matrix", GetAudioMatrixHandler)
+	api.POST("/audio/matrix", SetAudioMatrixHandler)
+	api.POST("/audio/meters/reset", ResetMetersHandler)
+	api.POST("/scripts/stopall", StopAllScriptsHandler)
+	api.POST("/scripts/:name/start", StartScriptHandler)
+	api.POST("/scripts/:name/stop", StopScriptHandler)
+	api.GET("/config/export", ExportConfigHandler)
+	api.POST("/config/import", ImportConfigHandler)
+	api.GET("/webhooks", GetWebhooksHandler)
+	api.POST("/webhooks", AddWebhookHandler)
+	api.PUT("/webhooks/:id", UpdateWebhookHandler)
+	api.DELETE("/webhooks/:id", DeleteWebhookHandler)
 }
 
 func main() {
+	flag.Parse()
 	log.Println("STARTING...")
+	functionErrorMarkers = strings.Split(*errorMarkers, ",")
+	setDestructiveFunctions(*destructive)
+	vmixClient.Transport = newvMixTransport(*maxIdleConns, *idleTimeout)
+	idempotencyKeys.ttl = *idempotentTTL
+	coalescedFunctions.window = *coalesceWindow
+	rawXML.enabled = *debug
+	uniqueInputNames = *uniqueNames
+	thumbnails.ttl = *thumbnailTTL
+	functionTimeouts.fallback = *functionTimeout
+	playbackEndThreshold = *playbackEnd
+	onConnect.script, onConnect.reconnect = *onConnectScript, *onReconnect
 
 	// Init vMix
-	var err error
-	vmix, err = vmixgo.NewVmix(*vmixaddr)
+	addr, err := vmixAddress(*vmixaddr, *vmixPort)
 	if err != nil {
+		log.Fatalln(err)
+	}
+	*vmixaddr = addr
+	base, err := normalizeBasePath(*basePath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := newvMix(*vmixaddr); err != nil {
 		panic(err)
 	}
+	stopPolling := make(chan struct{})
+	defer close(stopPolling)
+	if *refreshInterval > 0 {
+		go pollvMix(*refreshInterval, stopPolling)
+	}
 
 	// Init Gin router
 	gin.SetMode(gin.ReleaseMode)
@@ -172,19 +370,23 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	if base != "" {
+		index = withBaseHref(index, base)
+	}
 
 	favicon, err := staticFS.ReadFile("static/favicon.ico")
 	if err != nil {
 		panic(err)
 	}
 	// serve static files
-	r.GET("/", func(c *gin.Context) {
+	root := r.Group(base)
+	root.GET("/", func(c *gin.Context) {
 		c.Writer.WriteString(string(index))
 	})
-	r.GET("/favicon.ico", func(c *gin.Context) {
+	root.GET("/favicon.ico", func(c *gin.Context) {
 		c.Data(http.StatusOK, "image/x-icon", favicon)
 	})
-	r.GET("/css/*file", func(c *gin.Context) {
+	root.GET("/css/*file", func(c *gin.Context) {
 		file := c.Param("file")
 		b, err := staticFS.ReadFile("static/css" + file)
 		if err != nil {
@@ -193,7 +395,7 @@ func main() {
 		}
 		c.Data(http.StatusOK, "text/css", b)
 	})
-	r.GET("/js/*file", func(c *gin.Context) {
+	root.GET("/js/*file", func(c *gin.Context) {
 		file := c.Param("file")
 		b, err := staticFS.ReadFile("static/js" + file)
 		if err != nil {
@@ -202,7 +404,7 @@ func main() {
 		}
 		c.Data(http.StatusOK, "text/css", b)
 	})
-	r.GET("/img/*file", func(c *gin.Context) {
+	root.GET("/img/*file", func(c *gin.Context) {
 		file := c.Param("file")
 		b, err := staticFS.ReadFile("static/img" + file)
 		if err != nil {
@@ -211,7 +413,7 @@ func main() {
 		}
 		c.Data(http.StatusOK, "text/css", b)
 	})
-	r.GET("/fonts/*file", func(c *gin.Context) {
+	root.GET("/fonts/*file", func(c *gin.Context) {
 		file := c.Param("file")
 		b, err := staticFS.ReadFile("static/fonts" + file)
 		if err != nil {
@@ -220,7 +422,7 @@ func main() {
 		}
 		c.Data(http.StatusOK, "text/css", b)
 	})
-	r.GET("/multiviewer/*file", func(c *gin.Context) {
+	root.GET("/multiviewer/*file", func(c *gin.Context) {
 		file := c.Param("file")
 		b, err := multiviewFS.ReadFile("vMixMultiview" + file)
 		if err != nil {
@@ -230,20 +432,12 @@ func main() {
 		c.Data(http.StatusOK, "", b)
 	})
 
-	api := r.Group("/api")
-	{
-		api.GET("/vmix", GetvMixURLHandler)
-		api.GET("/inputs", GetInputsHandler)
-		api.GET("/functions", GetFunctionsHandler)
-		api.POST("/refresh", RefreshInputHandler)
-		api.POST("/multiple", DoMultipleFunctionsHandler)
-	}
+	api := root.Group("/api", TokenAuthMiddleware(*token), GzipMiddleware(*gzipEnabled))
+	registerAPI(api)
+	api.GET("/openapi.json", OpenAPIHandler(r.Routes))
 
-	url := fmt.Sprintf("http://localhost%s/", *hostaddr)
-	err = exec.Command("rundll32.exe", "url.dll,FileProtocolHandler", url).Start()
-	if err != nil {
-		log.Println("Failed to open link. ignoring...")
-		log.Printf("ERR : %v\n", err)
+	if shouldOpenBrowser() {
+		openURL(fmt.Sprintf("http://localhost%s%s/", *hostaddr, base))
 	}
 	log.Panicf("Failed to listen port %s : %v\n", *hostaddr, r.Run(*hostaddr))
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// setupTest points the global vmix instance at a mock server and returns a router serving /api.
+func setupTest(t *testing.T) (*vmixtest.Server, *gin.Engine) {
+	t.Helper()
+	s := vmixtest.NewServer(vmixtest.DefaultXML)
+	t.Cleanup(s.Close)
+
+	if err := newvMix(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	*vmixaddr = s.URL
+	r := gin.New()
+	registerAPI(r.Group("/api"))
+	return s, r
+}
+
+func doRequest(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetvMixURLHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/vmix", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), s.URL) {
+		t.Errorf("body %q does not contain %q", w.Body.String(), s.URL)
+	}
+}
+
+func TestGetInputsHandler(t *testing.T) {
+	_, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/inputs", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), "CAM 1") {
+		t.Errorf("body %q does not contain CAM 1", w.Body.String())
+	}
+}
+
+func TestRefreshInputHandler(t *testing.T) {
+	s, r := setupTest(t)
+	s.SetXML(`<vmix><inputs><input key="k" number="1" title="Refreshed">Refreshed</input></inputs></vmix>`)
+	w := doRequest(r, http.MethodPost, "/api/refresh", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(vmix.Inputs.Input) != 1 || vmix.Inputs.Input[0].Title != "Refreshed" {
+		t.Errorf("vmix not refreshed: %+v", vmix.Inputs.Input)
+	}
+}
+
+func TestDoMultipleFunctionsHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/multiple", `{"function":"Cut","queries":[{"key":"Input","value":"2"}],"num":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("len(Calls) = %d, want 3", len(calls))
+	}
+	for _, c := range calls {
+		if c.Function != "Cut" || c.Query.Get("Input") != "2" {
+			t.Errorf("unexpected call: %v", c.Query)
+		}
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/multiple", `{"function":"","num":1}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// errors from concurrent sends are all counted.
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	w = doRequest(r, http.MethodPost, "/api/multiple", `{"function":"Cut","queries":[{"key":"Input","value":"2"}],"num":20}`)
+	if w.Code != http.StatusAccepted || w.Body.String() != "Done with 20 errors" {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestMalformedFunctionRequests(t *testing.T) {
+	s, r := setupTest(t)
+	for _, path := range []string{"/api/multiple", "/api/function"} {
+		for body, want := range map[string]string{
+			``:                                 "Request body is empty",
+			`{"function":"Cut",`:               "unexpected end of body",
+			`{"function" "Cut"}`:               "Malformed JSON at offset 13",
+			`{"function":"Cut","num":"three"}`: `Field "num" must be int, got string`,
+			`{"function":1}`:                   `Field "function" must be string, got number`,
+		} {
+			if path == "/api/function" && strings.Contains(body, "num") {
+				// FunctionRequest has no num field.
+				continue
+			}
+			w := doRequest(r, http.MethodPost, path, body)
+			res := struct {
+				Error string `json:"error"`
+			}{}
+			json.Unmarshal(w.Body.Bytes(), &res)
+			if w.Code != http.StatusBadRequest || !strings.Contains(res.Error, want) {
+				t.Errorf("%s %s: %d %s, want 400 with %q", path, body, w.Code, w.Body.String(), want)
+			}
+		}
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("malformed requests sent functions: %+v", calls)
+	}
+}
+
+func TestInputOutputs(t *testing.T) {
+	setupTest(t)
+	vmix.FullScreen = true
+
+	want := map[string][]string{
+		"CAM 1":             {"Program", "Fullscreen"},
+		"opener.mp4":        {},
+		"Lower Third.gtzip": {"Overlay1"},
+	}
+	for _, input := range inputsResponse() {
+		if got := strings.Join(input.Outputs, ","); got != strings.Join(want[input.Title], ",") {
+			t.Errorf("outputs of %s = %v, want %v", input.Title, input.Outputs, want[input.Title])
+		}
+	}
+}
+
+func TestTokenAuthMiddleware(t *testing.T) {
+	r := gin.New()
+	r.GET("/api/vmix", TokenAuthMiddleware("secret"), GetvMixURLHandler)
+
+	for path, header := range map[string]string{
+		"/api/vmix":              "",
+		"/api/vmix?token=wrong":  "",
+		"/api/vmix?token=secret": "",
+		"/api/vmix?token=":       "Bearer secret",
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		r.ServeHTTP(w, req)
+		want := http.StatusUnauthorized
+		if strings.HasSuffix(path, "=secret") || header != "" {
+			want = http.StatusOK
+		}
+		if w.Code != want {
+			t.Errorf("%s (Authorization: %q) status = %d, want %d", path, header, w.Code, want)
+		}
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	_, r := setupTest(t)
+	r.GET("/api/openapi.json", OpenAPIHandler(r.Routes))
+
+	w := doRequest(r, http.MethodGet, "/api/openapi.json", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	for _, want := range []string{`"/api/inputs/{key}/fields"`, `"operationId":"DoMultipleFunctionsHandler"`, `"num":{"type":"integer"}`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("spec does not contain %s", want)
+		}
+	}
+}
+
+func TestGetHealthHandler(t *testing.T) {
+	s, r := setupTest(t)
+	vmixStatus.record(nil)
+
+	s.SetXML("not xml")
+	doRequest(r, http.MethodPost, "/api/refresh", "")
+	w := doRequest(r, http.MethodGet, "/api/health", "")
+	if !strings.Contains(w.Body.String(), `"ok":false`) || !strings.Contains(w.Body.String(), "Failed to unmarshal XML") {
+		t.Errorf("last error not reported: %s", w.Body.String())
+	}
+
+	s.SetXML(vmixtest.DefaultXML)
+	doRequest(r, http.MethodPost, "/api/refresh", "")
+	w = doRequest(r, http.MethodGet, "/api/health", "")
+	if !strings.Contains(w.Body.String(), `"ok":true`) || !strings.Contains(w.Body.String(), `"last_error":null`) {
+		t.Errorf("last error not cleared: %s", w.Body.String())
+	}
+
+	// Failed function does not change connectivity.
+	since := vmixStatus.ConnectedSince()
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	if err := sendFunction(vmix, "Cut", nil); err == nil {
+		t.Fatal("function did not fail")
+	}
+	w = doRequest(r, http.MethodGet, "/api/health", "")
+	if !strings.Contains(w.Body.String(), `"ok":true`) || !strings.Contains(w.Body.String(), `"last_function_error":{"error":"vMix returned error : Function Failed"`) {
+		t.Errorf("function error not reported separately: %s", w.Body.String())
+	}
+	if got := vmixStatus.ConnectedSince(); !got.Equal(since) {
+		t.Errorf("connected_since changed by function error: %v -> %v", since, got)
+	}
+}
+
+func TestGetStatusHandler(t *testing.T) {
+	_, r := setupTest(t)
+
+	w := doRequest(r, http.MethodGet, "/api/status", "")
+	for _, want := range []string{`"preset_name":"show.vmix"`, `"preset_saved":true`, `"version":"24.0.0.72"`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("status does not contain %s: %s", want, w.Body.String())
+		}
+	}
+	if strings.Contains(w.Body.String(), `"connected_since":null`) {
+		t.Errorf("connected_since not set: %s", w.Body.String())
+	}
+	if presetName("") != "" {
+		t.Error("unsaved preset must have empty name")
+	}
+	if !strings.Contains(w.Body.String(), `"warnings":[]`) {
+		t.Errorf("unexpected warnings: %s", w.Body.String())
+	}
+}
+
+func TestStateWarnings(t *testing.T) {
+	s, r := setupTest(t)
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<preview>2</preview>", "<preview>1</preview>", 1))
+	w := doRequest(r, http.MethodPost, "/api/refresh", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"warnings":["Preview and Program are both input 1"]`) {
+		t.Errorf("warning not reported: %d %s", w.Code, w.Body.String())
+	}
+	w = doRequest(r, http.MethodGet, "/api/status", "")
+	if !strings.Contains(w.Body.String(), `"Preview and Program are both input 1"`) {
+		t.Errorf("warning not reported in status: %s", w.Body.String())
+	}
+}
+
+func TestPingHandler(t *testing.T) {
+	_, r := setupTest(t)
+
+	w := doRequest(r, http.MethodGet, "/api/ping", "")
+	for _, want := range []string{`"connected":true`, `"vmixVersion":"24.0.0.72"`, `"lastRefreshMs":`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("ping does not contain %s: %s", want, w.Body.String())
+		}
+	}
+	if strings.Contains(w.Body.String(), `"lastRefreshMs":-1`) {
+		t.Errorf("last refresh not recorded: %s", w.Body.String())
+	}
+}
+
+func TestGetDebugXMLHandler(t *testing.T) {
+	s, r := setupTest(t)
+	if w := doRequest(r, http.MethodGet, "/api/debug/xml", ""); w.Code != http.StatusNotFound {
+		t.Errorf("disabled: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	rawXML.enabled = true
+	defer func() { rawXML = &xmlHistory{} }()
+	doRequest(r, http.MethodPost, "/api/refresh", "")
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<recording>False</recording>", "<recording>True</recording>", 1))
+	doRequest(r, http.MethodPost, "/api/refresh", "")
+
+	w := doRequest(r, http.MethodGet, "/api/debug/xml", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body struct {
+		Previous, Current *XMLSnapshot
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Previous == nil || !strings.Contains(body.Previous.XML, "<recording>False</recording>") {
+		t.Errorf("previous = %+v", body.Previous)
+	}
+	if body.Current == nil || !strings.Contains(body.Current.XML, "<recording>True</recording>") {
+		t.Errorf("current = %+v", body.Current)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// masterMeterKey is the peakHold key of master audio.
+const masterMeterKey = "master"
+
+// MeterLevel is current and held peak audio level. values are 0 to 1 amplitude.
+type MeterLevel struct {
+	Key     string  `json:"key"` // input key, "master" or bus such as "busA" .
+	MeterF1 float64 `json:"meterF1"`
+	MeterF2 float64 `json:"meterF2"`
+	PeakF1  float64 `json:"peakF1"` // highest meterF1 since last reset.
+	PeakF2  float64 `json:"peakF2"` // highest meterF2 since last reset.
+}
+
+// peakHold tracks highest meter levels seen on refresh, per input key and master.
+type peakHold struct {
+	mu    sync.Mutex
+	peaks map[string][2]float64
+}
+
+var meterPeaks = &peakHold{peaks: map[string][2]float64{}}
+
+func (p *peakHold) hold(key string, f1, f2 float64) {
+	peak := p.peaks[key]
+	p.peaks[key] = [2]float64{math.Max(peak[0], f1), math.Max(peak[1], f2)}
+}
+
+// busMeterKey returns peakHold key of bus. e.g. "busA" .
+func busMeterKey(bus string) string {
+	if bus == masterMeterKey {
+		return masterMeterKey
+	}
+	return "bus" + bus
+}
+
+// update holds meter levels of v and audio buses of e.
+func (p *peakHold) update(v *vmixgo.Vmix, e *vMixExtra) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(v.Audios.Master) > 0 {
+		p.hold(masterMeterKey, v.Audios.Master[0].MeterF1, v.Audios.Master[0].MeterF2)
+	}
+	for _, b := range e.Audio.Bus {
+		if b.Name() != masterMeterKey {
+			p.hold(busMeterKey(b.Name()), b.MeterF1, b.MeterF2)
+		}
+	}
+	for _, input := range v.Inputs.Input {
+		p.hold(input.Key, input.MeterF1, input.MeterF2)
+	}
+}
+
+// reset clears held peaks of keys. empty keys clears all.
+func (p *peakHold) reset(keys []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(keys) == 0 {
+		p.peaks = map[string][2]float64{}
+		return
+	}
+	for _, k := range keys {
+		delete(p.peaks, k)
+	}
+}
+
+// levels returns current meter levels of v with held peaks.
+func (p *peakHold) levels(v *vmixgo.Vmix) (master *MeterLevel, inputs []MeterLevel) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(v.Audios.Master) > 0 {
+		m := v.Audios.Master[0]
+		peak := p.peaks[masterMeterKey]
+		master = &MeterLevel{Key: masterMeterKey, MeterF1: m.MeterF1, MeterF2: m.MeterF2, PeakF1: peak[0], PeakF2: peak[1]}
+	}
+	inputs = make([]MeterLevel, 0, len(v.Inputs.Input))
+	for _, input := range v.Inputs.Input {
+		peak := p.peaks[input.Key]
+		inputs = append(inputs, MeterLevel{Key: input.Key, MeterF1: input.MeterF1, MeterF2: input.MeterF2, PeakF1: peak[0], PeakF2: peak[1]})
+	}
+	return master, inputs
+}
+
+// busLevel returns current meter level of bus in e with held peak.
+func (p *peakHold) busLevel(e *vMixExtra, bus string) (MeterLevel, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, b := range e.Audio.Bus {
+		if b.Name() == bus {
+			key := busMeterKey(bus)
+			peak := p.peaks[key]
+			return MeterLevel{Key: key, MeterF1: b.MeterF1, MeterF2: b.MeterF2, PeakF1: peak[0], PeakF2: peak[1]}, true
+		}
+	}
+	return MeterLevel{}, false
+}
+
+// audioBuses are valid bus names for "bus" query.
+var audioBuses = []string{masterMeterKey, "A", "B", "C", "D", "E", "F", "G"}
+
+// GetMetersHandler refreshes vMix and returns audio meters with held peaks for [GET] /api/audio/meters .
+// "bus" query such as "A" returns the level of that bus only.
+func GetMetersHandler(c *gin.Context) {
+	bus := c.Query("bus")
+	if bus != "" {
+		valid := false
+		for _, b := range audioBuses {
+			valid = valid || b == bus
+		}
+		if !valid {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Unknown bus %q. valid buses: %s", bus, strings.Join(audioBuses, ", ")),
+			})
+			return
+		}
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, vmixExtra := vmixSnapshot()
+	if bus != "" {
+		level, ok := meterPeaks.busLevel(vmixExtra, bus)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Bus %q is not enabled in vMix", bus),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"bus": level,
+		})
+		return
+	}
+	master, inputs := meterPeaks.levels(vmix)
+	c.JSON(http.StatusOK, gin.H{
+		"master": master,
+		"inputs": inputs,
+	})
+}
+
+// ResetMetersRequest Request JSON for ResetMetersHandler
+type ResetMetersRequest struct {
+	Keys []string `json:"keys"` // input keys, "master" or bus such as "busA" to reset. empty resets all.
+}
+
+// ResetMetersHandler clears held peaks for [POST] /api/audio/meters/reset and returns the cleared state.
+// Request body is optional.
+func ResetMetersHandler(c *gin.Context) {
+	req := ResetMetersRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	meterPeaks.reset(req.Keys)
+	vmix, _ := vmixSnapshot()
+	master, inputs := meterPeaks.levels(vmix)
+	c.JSON(http.StatusOK, gin.H{
+		"master": master,
+		"inputs": inputs,
+	})
+}
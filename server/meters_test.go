@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestMeterPeakHold(t *testing.T) {
+	s, r := setupTest(t)
+	meterPeaks.reset(nil)
+
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, `meterF1="0.1"`, `meterF1="0.8"`, 1))
+	doRequest(r, http.MethodGet, "/api/audio/meters", "")
+	s.SetXML(vmixtest.DefaultXML)
+	w := doRequest(r, http.MethodGet, "/api/audio/meters", "")
+
+	res := struct {
+		Master *MeterLevel  `json:"master"`
+		Inputs []MeterLevel `json:"inputs"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if res.Inputs[0].MeterF1 != 0.1 || res.Inputs[0].PeakF1 != 0.8 {
+		t.Errorf("unexpected level: %+v", res.Inputs[0])
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/audio/meters/reset", "")
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if res.Inputs[0].PeakF1 != 0 || res.Master.PeakF1 != 0 {
+		t.Errorf("peaks not reset: %+v %+v", res.Inputs[0], res.Master)
+	}
+
+	// Chunked empty body has unknown ContentLength.
+	req := httptest.NewRequest(http.MethodPost, "/api/audio/meters/reset", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("chunked empty body: status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if w := doRequest(r, http.MethodPost, "/api/audio/meters/reset", `{"keys":"master"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetMetersHandlerBus(t *testing.T) {
+	_, r := setupTest(t)
+	meterPeaks.reset(nil)
+
+	w := doRequest(r, http.MethodGet, "/api/audio/meters?bus=A", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	res := struct {
+		Bus MeterLevel `json:"bus"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if res.Bus.Key != "busA" || res.Bus.MeterF1 != 0.3 || res.Bus.PeakF1 != 0.3 {
+		t.Errorf("unexpected level: %+v", res.Bus)
+	}
+
+	for bus, want := range map[string]int{"B": http.StatusNotFound, "Z": http.StatusBadRequest} {
+		if w := doRequest(r, http.MethodGet, "/api/audio/meters?bus="+bus, ""); w.Code != want {
+			t.Errorf("bus %s: status = %d, want %d", bus, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FunctionMetrics is aggregated result of a function sent to vMix.
+type FunctionMetrics struct {
+	Success      int     `json:"success"`        // successful sends.
+	Errors       int     `json:"errors"`         // failed sends, including timeouts and vMix errors.
+	AvgLatencyMs float64 `json:"avg_latency_ms"` // average time until vMix responded, of both successful and failed sends.
+	LastError    string  `json:"last_error,omitempty"`
+
+	totalLatency time.Duration
+}
+
+// metricsStats aggregates results of functions sent to vMix to find flaky controls.
+type metricsStats struct {
+	mu      sync.Mutex
+	metrics map[string]*FunctionMetrics
+}
+
+// record adds result of funcname which took latency.
+func (s *metricsStats) record(funcname string, err error, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.metrics[funcname]
+	if !ok {
+		m = &FunctionMetrics{}
+		s.metrics[funcname] = m
+	}
+	if err != nil {
+		m.Errors++
+		m.LastError = err.Error()
+	} else {
+		m.Success++
+	}
+	m.totalLatency += latency
+	m.AvgLatencyMs = float64(m.totalLatency) / float64(m.Success+m.Errors) / float64(time.Millisecond)
+}
+
+// get returns copy of metrics.
+func (s *metricsStats) get() map[string]FunctionMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metrics := make(map[string]FunctionMetrics, len(s.metrics))
+	for k, v := range s.metrics {
+		metrics[k] = *v
+	}
+	return metrics
+}
+
+// reset clears all metrics.
+func (s *metricsStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = map[string]*FunctionMetrics{}
+}
+
+// functionMetrics is metrics of functions sent by this server since start or last reset.
+var functionMetrics = &metricsStats{metrics: map[string]*FunctionMetrics{}}
+
+// GetFunctionMetricsHandler returns per-function metrics for [GET] /api/metrics/functions .
+func GetFunctionMetricsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"functions": functionMetrics.get(),
+	})
+}
+
+// ResetFunctionMetricsHandler clears per-function metrics for [DELETE] /api/metrics/functions .
+func ResetFunctionMetricsHandler(c *gin.Context) {
+	functionMetrics.reset()
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestFunctionMetricsHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	functionMetrics.reset()
+
+	doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut"}`)
+	doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut"}`)
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut"}`)
+
+	w := doRequest(r, http.MethodGet, "/api/metrics/functions", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body struct {
+		Functions map[string]FunctionMetrics `json:"functions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	m := body.Functions["Cut"]
+	if m.Success != 2 || m.Errors != 1 || m.LastError == "" || m.AvgLatencyMs <= 0 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+
+	if w := doRequest(r, http.MethodDelete, "/api/metrics/functions", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if len(functionMetrics.get()) != 0 {
+		t.Error("metrics not reset")
+	}
+}
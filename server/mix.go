@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// maxMix is the number of mixes in vMix 4K and Pro. Mix 1 is the main mix.
+const maxMix = 16
+
+// mixes returns every mix including main mix 1, ordered by number.
+// /api/mixes, /api/status and /api/tally all read mixes through it.
+func mixes(v *vmixgo.Vmix, e *vMixExtra) []Mix {
+	mixes := []Mix{{Number: 1, Active: v.Active, Preview: v.Preview}}
+	for _, m := range e.Mixes {
+		if m.Number > 1 {
+			mixes = append(mixes, m)
+		}
+	}
+	sort.Slice(mixes, func(i, j int) bool { return mixes[i].Number < mixes[j].Number })
+	return mixes
+}
+
+// MixState is a mix with input keys resolved for tally.
+type MixState struct {
+	Mix
+	ActiveInput  string `json:"active_input"`  // input key on Output of the mix. empty if unknown.
+	PreviewInput string `json:"preview_input"` // input key on Preview of the mix. empty if unknown.
+}
+
+// GetMixesHandler returns active and preview inputs of every mix for [GET] /api/mixes .
+func GetMixesHandler(c *gin.Context) {
+	keys := map[uint]string{}
+	vmix, vmixExtra := vmixSnapshot()
+	for _, in := range vmix.Inputs.Input {
+		keys[in.Number] = in.Key
+	}
+	states := []MixState{}
+	for _, m := range mixes(vmix, vmixExtra) {
+		states = append(states, MixState{Mix: m, ActiveInput: keys[m.Active], PreviewInput: keys[m.Preview]})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"mixes": states,
+	})
+}
+
+// SetMixActive cuts input to Output of mix. Mix param of vMix functions starts from 0 for mix 1.
+func SetMixActive(v *vmixgo.Vmix, mix int, input string) error {
+	if mix < 1 || mix > maxMix {
+		return fmt.Errorf("mix must be between 1 and %d", maxMix)
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Mix"] = strconv.Itoa(mix - 1)
+	return sendFunction(v, "ActiveInput", params)
+}
+
+// MixActiveRequest Request JSON for SetMixActiveHandler
+type MixActiveRequest struct {
+	Input string `json:"input"` // input key, number or title.
+}
+
+// SetMixActiveHandler sets active input of mix for [POST] /api/mix/:n/active .
+func SetMixActiveHandler(c *gin.Context) {
+	mix, err := strconv.Atoi(c.Param("n"))
+	if err != nil || mix < 1 || mix > maxMix {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("mix must be between 1 and %d", maxMix),
+		})
+		return
+	}
+	req := MixActiveRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := SetMixActive(vmix, mix, input.Key); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"mix":   mix,
+		"input": input.Key,
+	})
+}
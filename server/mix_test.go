@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetMixActiveHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	w := doRequest(r, http.MethodPost, "/api/mix/2/active", `{"input":"CAM 1"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "ActiveInput" || calls[0].Query.Get("Mix") != "1" || calls[0].Query.Get("Input") != vmix.Inputs.Input[0].Key {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	for _, path := range []string{"/api/mix/0/active", "/api/mix/17/active", "/api/mix/main/active"} {
+		if w := doRequest(r, http.MethodPost, path, `{"input":"1"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	w = doRequest(r, http.MethodGet, "/api/status", "")
+	if !strings.Contains(w.Body.String(), `"mix_active":{"1":1,"2":3}`) {
+		t.Errorf("mix actives not in status: %s", w.Body.String())
+	}
+}
+
+func TestGetMixesHandler(t *testing.T) {
+	_, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/mixes", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	keys := vmix.Inputs.Input
+	want := `{"mixes":[` +
+		`{"number":1,"active":1,"preview":2,"active_input":"` + keys[0].Key + `","preview_input":"` + keys[1].Key + `"},` +
+		`{"number":2,"active":3,"preview":1,"active_input":"` + keys[2].Key + `","preview_input":"` + keys[0].Key + `"}]}`
+	if w.Body.String() != want {
+		t.Errorf("body = %s, want %s", w.Body.String(), want)
+	}
+}
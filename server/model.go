@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// vMixExtra contains vMix API XML elements which vmix-go does not parse.
+type vMixExtra struct {
+	XMLName xml.Name `xml:"vmix"`
+	// Overlays slice with current input number. 0 means overlay channel is off.
+	Overlays struct {
+		Overlay []struct {
+			Number uint `xml:"number,attr"`
+			Input  uint `xml:",chardata"`
+		} `xml:"overlay"`
+	} `xml:"overlays"`
+	// Audio buses including master. e.g. <master>, <busA> ...
+	Audio struct {
+		Bus []AudioBus `xml:",any"`
+	} `xml:"audio"`
+	// Mixes other than main mix. e.g. <mix number="2"><active>1</active><preview>2</preview></mix>
+	Mixes []Mix `xml:"mix"`
+	// Inputs with elements vmix-go does not parse, such as title fields.
+	Inputs struct {
+		Input []InputExtra `xml:"input"`
+	} `xml:"inputs"`
+}
+
+// Mix is a <mix> element. Active and Preview are input numbers.
+type Mix struct {
+	Number  uint `xml:"number,attr" json:"number"`
+	Active  uint `xml:"active" json:"active"`
+	Preview uint `xml:"preview" json:"preview"`
+}
+
+// InputExtra is an <input> element parsed by vMixExtra.
+type InputExtra struct {
+	Key        string      `xml:"key,attr"`
+	ShortTitle string      `xml:"shortTitle,attr"` // vmix-go misspells the attribute, so it is parsed here.
+	Text       []TitleText `xml:"text"`
+	Image      []TitleText `xml:"image"`
+	Position   struct {
+		CropX1 string `xml:"cropX1,attr"`
+		CropY1 string `xml:"cropY1,attr"`
+		CropX2 string `xml:"cropX2,attr"`
+		CropY2 string `xml:"cropY2,attr"`
+	} `xml:"position"`
+}
+
+// Fields returns text and image fields ordered by index.
+func (i InputExtra) Fields() []TitleText {
+	fields := make([]TitleText, 0, len(i.Text)+len(i.Image))
+	fields = append(fields, i.Text...)
+	fields = append(fields, i.Image...)
+	sort.Slice(fields, func(a, b int) bool {
+		return fields[a].Index < fields[b].Index
+	})
+	return fields
+}
+
+// TitleText is a title text or image field in <input> element. Value is text or image path.
+type TitleText struct {
+	Index int    `xml:"index,attr"`
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// input returns InputExtra by input key.
+func (e *vMixExtra) input(key string) (InputExtra, bool) {
+	for _, i := range e.Inputs.Input {
+		if i.Key == key {
+			return i, true
+		}
+	}
+	return InputExtra{}, false
+}
+
+// textAt returns current value of title field by index.
+func (i InputExtra) textAt(index int) (string, bool) {
+	for _, t := range i.Text {
+		if t.Index == index {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// text returns current value of title field name.
+func (i InputExtra) text(name string) (string, bool) {
+	for _, t := range i.Text {
+		if t.Name == name {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// AudioBus is an audio bus in <audio> element.
+type AudioBus struct {
+	XMLName xml.Name
+	Volume  float64 `xml:"volume,attr"`
+	Muted   bool    `xml:"muted,attr"`
+	MeterF1 float64 `xml:"meterF1,attr"`
+	MeterF2 float64 `xml:"meterF2,attr"`
+}
+
+// Name returns bus name. "master" or "A" to "G" .
+func (b AudioBus) Name() string {
+	return strings.TrimPrefix(b.XMLName.Local, "bus")
+}
+
+// vmixExtra is parsed together with vmix on every refresh.
+var vmixExtra = &vMixExtra{}
+
+// stateMu guards vmix and vmixExtra, which are replaced together on every refresh.
+// Other code reads them by vmixSnapshot .
+var stateMu sync.RWMutex
+
+// refreshMu serializes refreshes, so fetch, swap and event diff of a refresh are not interleaved with another.
+var refreshMu sync.Mutex
+
+// vmixSnapshot returns vmix and vmixExtra parsed from the same refresh.
+// They are replaced, never modified, by later refreshes, so the caller can keep reading them without lock.
+func vmixSnapshot() (*vmixgo.Vmix, *vMixExtra) {
+	stateMu.RLock()
+	defer stateMu.RUnlock()
+	return vmix, vmixExtra
+}
+
+// vmixAddress merges port into vMix address and validates it.
+// Port in addr takes precedence over port. port 0 keeps addr as is.
+// Scheme can be omitted. e.g. "192.168.1.10" with port 8088 is "http://192.168.1.10:8088" .
+func vmixAddress(addr string, port int) (string, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("Invalid vMix address %q : %v", addr, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("Invalid vMix address %q : scheme must be http or https", addr)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("Invalid vMix address %q : host is empty", addr)
+	}
+	if port < 0 || port > 65535 {
+		return "", fmt.Errorf("Invalid vMix port %d", port)
+	}
+	if u.Port() == "" && port != 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+	}
+	return u.String(), nil
+}
+
+// newvMix connects to vMix API on addr and loads vmix and vmixExtra.
+func newvMix(addr string) error {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("Failed to parse URL... %v", err)
+	}
+	u.Path = path.Join(u.Path, "/api")
+	stateMu.Lock()
+	vmix = &vmixgo.Vmix{Addr: u}
+	stateMu.Unlock()
+	return refreshvMix()
+}
+
+// refreshvMix fetches vMix API XML once and updates both vmix and vmixExtra.
+// Concurrent refreshes run one by one, so webhook events are diffed against the previous refresh exactly once.
+func refreshvMix() error {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	prev, prevExtra := vmixSnapshot()
+	prevErr := vmixStatus.LastError()
+	err := fetchvMix()
+	vmixStatus.record(err)
+	next, nextExtra := vmixSnapshot()
+	onConnect.refreshed(next, err)
+	if err != nil {
+		if prevErr == nil {
+			webhooks.dispatch(WebhookEvent{Event: EventHostDisconnected, Time: time.Now()})
+		}
+		return err
+	}
+	vmixStatus.refreshed()
+	meterPeaks.update(next, nextExtra)
+	thumbnails.prune(next)
+	for _, e := range webhookEventsBetween(prev, next, prevExtra, nextExtra) {
+		webhooks.dispatch(e)
+	}
+	return nil
+}
+
+// pollvMix refreshes vMix every interval until stop is closed, so webhook events fire without API requests.
+func pollvMix(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := refreshvMix(); err != nil {
+				log.Printf("Failed to refresh vMix : %v\n", err)
+			}
+		}
+	}
+}
+
+// commaDecimalAttr matches float attributes written with decimal comma, which vMix emits on some Windows locales.
+var commaDecimalAttr = regexp.MustCompile(`\b(volume|balance|meterF1|meterF2|headphonesVolume|gainDb|panX|panY|zoomX|zoomY|speed)="(-?\d+),(\d+)"`)
+
+// normalizeDecimals replaces decimal comma in float attributes with dot. e.g. volume="12,5" -> volume="12.5" .
+func normalizeDecimals(body []byte) []byte {
+	return commaDecimalAttr.ReplaceAll(body, []byte(`$1="$2.$3"`))
+}
+
+// maxErrorBodySnippet is the longest part of response body included in HTTPStatusError.
+const maxErrorBodySnippet = 200
+
+// HTTPStatusError is returned when vMix responds to XML or function request with non-2xx status,
+// such as 404 from a web server other than vMix Web Controller on the address.
+type HTTPStatusError struct {
+	StatusCode int    // e.g. 404 .
+	Status     string // e.g. "404 Not Found" .
+	Body       string // beginning of response body.
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("vMix returned %s", e.Status)
+	}
+	return fmt.Sprintf("vMix returned %s : %s", e.Status, e.Body)
+}
+
+// fetchvMix fetches and parses vMix API XML, then replaces vmix and vmixExtra.
+func fetchvMix() error {
+	current, _ := vmixSnapshot()
+	resp, err := vmixClient.Get(current.Addr.String())
+	if err != nil {
+		return fmt.Errorf("Failed to connect vmix... %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySnippet))
+		return &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       strings.TrimSpace(string(snippet)),
+		}
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed to Read body... %v", err)
+	}
+	rawXML.record(body)
+	body = normalizeDecimals(body)
+	v := vmixgo.Vmix{}
+	if err := xml.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("Failed to unmarshal XML... %v", err)
+	}
+	e := vMixExtra{}
+	if err := xml.Unmarshal(body, &e); err != nil {
+		return fmt.Errorf("Failed to unmarshal XML... %v", err)
+	}
+	for i, input := range v.Inputs.Input {
+		if extra, ok := e.input(input.Key); ok {
+			v.Inputs.Input[i].ShortTitle = extra.ShortTitle
+		}
+	}
+	v.Addr = current.Addr
+	stateMu.Lock()
+	vmix, vmixExtra = &v, &e
+	stateMu.Unlock()
+	return nil
+}
+
+// findInput finds input from vmix by key, number or title.
+func findInput(input string) (vmixgo.Input, bool) {
+	vmix, _ := vmixSnapshot()
+	for _, in := range vmix.Inputs.Input {
+		if in.Key == input || fmt.Sprint(in.Number) == input || in.Title == input {
+			return in, true
+		}
+	}
+	return vmixgo.Input{}, false
+}
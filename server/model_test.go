@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestRefreshCommaDecimals(t *testing.T) {
+	s, _ := setupTest(t)
+	xml := strings.NewReplacer(
+		`volume="80"`, `volume="80,5"`,
+		`meterF1="0.1"`, `meterF1="0,1"`,
+		`headphonesVolume="74.36"`, `headphonesVolume="74,36"`,
+	).Replace(vmixtest.DefaultXML)
+	s.SetXML(xml)
+
+	if err := refreshvMix(); err != nil {
+		t.Fatal(err)
+	}
+	if got := vmix.Inputs.Input[1].Volume; got != 80.5 {
+		t.Errorf("Volume = %v, want 80.5", got)
+	}
+	if got := vmix.Inputs.Input[0].MeterF1; got != 0.1 {
+		t.Errorf("MeterF1 = %v, want 0.1", got)
+	}
+	if got := vmix.Audios.Master[0].HeadphonesVolume; got != 74.36 {
+		t.Errorf("HeadphonesVolume = %v, want 74.36", got)
+	}
+}
+
+func TestVmixAddress(t *testing.T) {
+	for _, tt := range []struct {
+		addr string
+		port int
+		want string
+		err  bool
+	}{
+		{"http://localhost:8088", 0, "http://localhost:8088", false},
+		{"http://localhost:8088", 9000, "http://localhost:8088", false},
+		{"http://192.168.1.10", 9000, "http://192.168.1.10:9000", false},
+		{"192.168.1.10", 8088, "http://192.168.1.10:8088", false},
+		{"https://vmix.local/", 443, "https://vmix.local:443/", false},
+		{"ftp://localhost", 0, "", true},
+		{"http://", 8088, "", true},
+		{"localhost", 70000, "", true},
+	} {
+		got, err := vmixAddress(tt.addr, tt.port)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("vmixAddress(%q, %d) = %q, %v. want %q", tt.addr, tt.port, got, err, tt.want)
+		}
+	}
+}
+
+func TestRefreshShortTitle(t *testing.T) {
+	setupTest(t)
+	for i, want := range []struct{ title, shortTitle string }{
+		{"CAM 1", "CAM 1"},
+		{"opener.mp4", "opener"},
+		{"Lower Third.gtzip", "Lower Third"},
+	} {
+		input := vmix.Inputs.Input[i]
+		if input.Title != want.title || input.ShortTitle != want.shortTitle {
+			t.Errorf("input %d: Title = %q, ShortTitle = %q, want %q, %q", i+1, input.Title, input.ShortTitle, want.title, want.shortTitle)
+		}
+	}
+}
+
+func TestRefreshHTTPStatusError(t *testing.T) {
+	s, _ := setupTest(t)
+	for _, tt := range []struct {
+		status int
+		body   string
+		want   string
+	}{
+		{http.StatusNotFound, "Not Found", "vMix returned 404 Not Found : Not Found"},
+		{http.StatusInternalServerError, strings.Repeat("x", 500), "vMix returned 500 Internal Server Error : " + strings.Repeat("x", maxErrorBodySnippet)},
+	} {
+		s.SetXMLResponse(tt.status, tt.body)
+		err := refreshvMix()
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("%d: err = %v, want HTTPStatusError", tt.status, err)
+		}
+		if statusErr.StatusCode != tt.status || err.Error() != tt.want {
+			t.Errorf("%d: err = %q, want %q", tt.status, err.Error(), tt.want)
+		}
+	}
+	// previous state is kept
+	if vmix.Version == "" {
+		t.Error("vmix cleared by failed refresh")
+	}
+}
+
+func TestRefreshConcurrent(t *testing.T) {
+	s, r := setupTest(t)
+
+	var lives int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lives, 1)
+	}))
+	defer receiver.Close()
+	hook := webhooks.add(Webhook{URL: receiver.URL, Events: []string{EventInputLive}})
+	defer webhooks.remove(hook.ID)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		pollvMix(time.Millisecond, stop)
+		close(done)
+	}()
+
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<active>1</active>", "<active>2</active>", 1))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			refreshvMix()
+		}()
+		go func() {
+			defer wg.Done()
+			doRequest(r, http.MethodGet, "/api/inputs", "")
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pollvMix did not stop")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&lives); n != 1 {
+		t.Errorf("input_live delivered %d times, want 1", n)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiRequestBodies maps handler names to their JSON request body type, used for OpenAPI requestBody schema.
+var apiRequestBodies = map[string]interface{}{
+	"DoFunctionHandler":          FunctionRequest{},
+	"DoMultipleFunctionsHandler": DoMultipleFunctionsRequest{},
+	"BulkInputsHandler":          BulkInputsRequest{},
+	"SetFieldsHandler":           SetFieldsRequest{},
+	"SetCropHandler":             SetCropRequest{},
+	"SetColourHandler":           SetColourRequest{},
+	"TakeInputHandler":           TakeRequest{},
+	"TransitionHandler":          TransitionRequest{},
+	"SetFaderHandler":            FaderRequest{},
+	"RouteHandler":               RouteRequest{},
+	"SetOverlayHandler":          OverlayRequest{},
+	"SetBalanceHandler":          SetBalanceRequest{},
+	"SetAudioMatrixHandler":      SetAudioMatrixRequest{},
+	"StartScriptHandler":         StartScriptRequest{},
+	"SnapshotHandler":            SnapshotRequest{},
+	"SetChannelMatrixHandler":    ChannelMatrixRequest{},
+	"SetMixActiveHandler":        MixActiveRequest{},
+	"AdjustVolumeHandler":        AdjustVolumeRequest{},
+	"StingerHandler":             StingerRequest{},
+	"IdentifyInputHandler":       IdentifyRequest{},
+	"TransitionTemplateHandler":  TransitionTemplateRequest{},
+	"SetHeadphonesHandler":       HeadphonesRequest{},
+	"SetLogoHandler":             LogoRequest{},
+	"RenameInputHandler":         RenameInputRequest{},
+	"SwitcherHandler":            SwitcherRequest{},
+	"CrossfadeHandler":           CrossfadeRequest{},
+	"RecallOverlayPresetHandler": OverlayPresetRequest{},
+	"BulkVolumeHandler":          BulkVolumeRequest{},
+	"SaveAudioSnapshotHandler":   AudioSnapshotRequest{},
+	"SetVisibilityHandler":       VisibilityRequest{},
+	"SetColourFieldHandler":      ColourFieldRequest{},
+	"NormalizeHandler":           NormalizeRequest{},
+	"ResetMetersHandler":         ResetMetersRequest{},
+	"AddWebhookHandler":          Webhook{},
+	"UpdateWebhookHandler":       Webhook{},
+	"ImportConfigHandler":        ConfigBundle{},
+}
+
+// jsonSchema returns minimal JSON schema of t.
+func jsonSchema(t reflect.Type) gin.H {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonSchema(t.Elem())
+	case reflect.String:
+		return gin.H{"type": "string"}
+	case reflect.Bool:
+		return gin.H{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return gin.H{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return gin.H{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return gin.H{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return gin.H{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		props := gin.H{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = jsonSchema(f.Type)
+		}
+		return gin.H{"type": "object", "properties": props}
+	default:
+		return gin.H{}
+	}
+}
+
+// openAPIPath converts gin path to OpenAPI path and its path parameters. e.g. "/inputs/:key" -> "/inputs/{key}" .
+func openAPIPath(p string) (string, []string) {
+	params := []string{}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// OpenAPIHandler returns OpenAPI 3 spec of /api routes returned by routes for [GET] /api/openapi.json .
+// Routes are listed under the group of the handler, so -base-path is included in paths.
+func OpenAPIHandler(routes func() gin.RoutesInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefix := path.Dir(c.FullPath()) + "/"
+		paths := gin.H{}
+		for _, r := range routes() {
+			if !strings.HasPrefix(r.Path, prefix) {
+				continue
+			}
+			p, params := openAPIPath(r.Path)
+			name := r.Handler[strings.LastIndex(r.Handler, ".")+1:]
+			op := gin.H{
+				"operationId": name,
+				"responses": gin.H{
+					"200": gin.H{
+						"description": "OK",
+						"content":     gin.H{"application/json": gin.H{}},
+					},
+				},
+			}
+			if len(params) > 0 {
+				ps := make([]gin.H, 0, len(params))
+				for _, param := range params {
+					ps = append(ps, gin.H{
+						"name":     param,
+						"in":       "path",
+						"required": true,
+						"schema":   gin.H{"type": "string"},
+					})
+				}
+				op["parameters"] = ps
+			}
+			if body, ok := apiRequestBodies[name]; ok {
+				op["requestBody"] = gin.H{
+					"required": true,
+					"content": gin.H{
+						"application/json": gin.H{"schema": jsonSchema(reflect.TypeOf(body))},
+					},
+				}
+			}
+			if _, ok := paths[p]; !ok {
+				paths[p] = gin.H{}
+			}
+			paths[p].(gin.H)[strings.ToLower(r.Method)] = op
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"openapi": "3.0.3",
+			"info": gin.H{
+				"title":   "vmix-utility",
+				"version": "1.0.0",
+			},
+			"paths": paths,
+		})
+	}
+}
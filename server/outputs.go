@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// inputOutputs returns outputs and overlay channels which input currently feeds, resolved from states of outputStates,
+// so /api/inputs, /api/outputs and /api/tally always agree.
+func inputOutputs(v *vmixgo.Vmix, e *vMixExtra, states []OutputState, input vmixgo.Input) []string {
+	outputs := []string{}
+	if input.Number == 0 {
+		return outputs
+	}
+	for _, o := range states {
+		if o.Input == input.Key || (o.Source == "Program" && input.Number == v.Active) {
+			outputs = append(outputs, o.Output)
+		}
+	}
+	for _, o := range e.Overlays.Overlay {
+		if o.Input == input.Number {
+			outputs = append(outputs, fmt.Sprintf("Overlay%d", o.Number))
+		}
+	}
+	return outputs
+}
+
+// outputFunctions maps output names to vMix SetOutput functions.
+var outputFunctions = map[string]string{
+	"2":           "SetOutput2",
+	"3":           "SetOutput3",
+	"4":           "SetOutput4",
+	"External2":   "SetOutputExternal2",
+	"Fullscreen":  "SetOutputFullscreen",
+	"Fullscreen2": "SetOutputFullscreen2",
+}
+
+// SetOutputInput routes input to output. output is one of outputFunctions keys.
+func SetOutputInput(v *vmixgo.Vmix, output string, input string) error {
+	function, ok := outputFunctions[output]
+	if !ok {
+		return fmt.Errorf("Unknown output %q", output)
+	}
+	params := make(map[string]string)
+	params["Value"] = "Input"
+	params["Input"] = input
+	if err := sendFunction(v, function, params); err != nil {
+		return err
+	}
+	outputRoutes.set(v.Addr.String(), output, input)
+	return nil
+}
+
+// outputRouteStore remembers inputs routed by SetOutputInput, as vMix XML does not report them.
+type outputRouteStore struct {
+	mu     sync.Mutex
+	routes map[string]string // vMix address + output name to input key
+}
+
+var outputRoutes = &outputRouteStore{routes: map[string]string{}}
+
+func (s *outputRouteStore) set(host, output, input string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[host+"\x00"+output] = input
+}
+
+func (s *outputRouteStore) get(host, output string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	input, ok := s.routes[host+"\x00"+output]
+	return input, ok
+}
+
+// outputNames are outputs listed by GetOutputsHandler in order.
+var outputNames = []string{"Program", "Fullscreen", "External", "2", "3", "4", "Fullscreen2", "External2"}
+
+// OutputState is what an output currently shows.
+type OutputState struct {
+	Output string `json:"output"`          // output name.
+	Source string `json:"source"`          // e.g. "Input", "Program", "MultiView", "Off". empty if unknown.
+	Input  string `json:"input,omitempty"` // input key if source is an input.
+	Title  string `json:"title,omitempty"` // input title if source is an input.
+	Origin string `json:"origin"`          // "xml" if derived from vMix XML, "routed" if set via this server, "assumed" for vMix default, or "unknown".
+}
+
+// outputStates returns current state of every output.
+// vMix XML does not report SetOutput routing, so outputs other than Program are resolved from
+// the last route set via this server, then from vMix defaults. Routes set from vMix itself are not known.
+func outputStates(v *vmixgo.Vmix, e *vMixExtra) []OutputState {
+	inputByNumber := func(n uint) (vmixgo.Input, bool) {
+		for _, in := range v.Inputs.Input {
+			if in.Number == n {
+				return in, true
+			}
+		}
+		return vmixgo.Input{}, false
+	}
+	withInput := func(s OutputState, in vmixgo.Input) OutputState {
+		s.Source, s.Input, s.Title = "Input", in.Key, in.Title
+		return s
+	}
+
+	states := make([]OutputState, 0, len(outputNames))
+	for _, name := range outputNames {
+		s := OutputState{Output: name, Origin: "unknown"}
+		switch key, routed := outputRoutes.get(v.Addr.String(), name); {
+		case name == "Program":
+			s.Origin = "xml"
+			if in, ok := inputByNumber(v.Active); ok {
+				s = withInput(s, in)
+			}
+		case routed:
+			s.Origin = "routed"
+			for _, in := range v.Inputs.Input {
+				if in.Key == key {
+					s = withInput(s, in)
+				}
+			}
+		case name == "Fullscreen" && !v.FullScreen, name == "External" && !v.External:
+			s.Source, s.Origin = "Off", "xml"
+		case name == "Fullscreen", name == "External":
+			s.Source, s.Origin = "Program", "assumed"
+		}
+		states = append(states, s)
+	}
+	return states
+}
+
+// GetOutputsHandler returns what each output currently shows for [GET] /api/outputs .
+func GetOutputsHandler(c *gin.Context) {
+	vmix, vmixExtra := vmixSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"outputs": outputStates(vmix, vmixExtra),
+	})
+}
+
+// RouteRequest Request JSON for RouteHandler
+type RouteRequest struct {
+	Routes map[string]string `json:"routes"` // output name to input key, number or title. e.g. {"2":"CAM 1"} .
+}
+
+// OutputResult is a result of routing single output.
+type OutputResult struct {
+	Output string `json:"output"`          // output name.
+	Input  string `json:"input"`           // input key.
+	Error  string `json:"error,omitempty"` // error message. empty on success.
+}
+
+// RouteHandler routes inputs to outputs for [POST] /api/route .
+// Every output and input is validated before any function is sent.
+func RouteHandler(c *gin.Context) {
+	req := RouteRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if len(req.Routes) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Routes empty",
+		})
+		return
+	}
+
+	outputs := make([]string, 0, len(req.Routes))
+	for output := range req.Routes {
+		outputs = append(outputs, output)
+	}
+	sort.Strings(outputs)
+
+	results := make([]OutputResult, 0, len(outputs))
+	for _, output := range outputs {
+		if _, ok := outputFunctions[output]; !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Unknown output %q", output),
+			})
+			return
+		}
+		input, ok := findInput(req.Routes[output])
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Input %q not found", req.Routes[output]),
+			})
+			return
+		}
+		results = append(results, OutputResult{Output: output, Input: input.Key})
+	}
+
+	status := http.StatusOK
+	vmix, _ := vmixSnapshot()
+	for i, r := range results {
+		if err := SetOutputInput(vmix, r.Output, r.Input); err != nil {
+			results[i].Error = err.Error()
+			status = http.StatusAccepted
+		}
+	}
+	c.JSON(status, gin.H{
+		"results": results,
+	})
+}
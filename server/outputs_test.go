@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRouteHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/route", `{"routes":{"2":"CAM 1","Fullscreen":"3"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Function != "SetOutput2" || calls[0].Query.Get("Value") != "Input" || calls[0].Query.Get("Input") != vmix.Inputs.Input[0].Key {
+		t.Errorf("unexpected call: %v", calls[0].Query)
+	}
+	if calls[1].Function != "SetOutputFullscreen" || calls[1].Query.Get("Input") != vmix.Inputs.Input[2].Key {
+		t.Errorf("unexpected call: %v", calls[1].Query)
+	}
+
+	s.Reset()
+	w = doRequest(r, http.MethodPost, "/api/route", `{"routes":{"2":"CAM 1","3":"missing"}}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid input")
+	}
+}
+
+func TestGetOutputsHandler(t *testing.T) {
+	_, r := setupTest(t)
+	get := func() map[string]OutputState {
+		t.Helper()
+		w := doRequest(r, http.MethodGet, "/api/outputs", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		var body struct {
+			Outputs []OutputState `json:"outputs"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		states := map[string]OutputState{}
+		for _, o := range body.Outputs {
+			states[o.Output] = o
+		}
+		return states
+	}
+
+	// Outputs other than Program degrade to defaults and routes set via this server.
+	states := get()
+	if p := states["Program"]; p.Input != vmix.Inputs.Input[0].Key || p.Origin != "xml" {
+		t.Errorf("Program = %+v", p)
+	}
+	if f := states["Fullscreen"]; f.Source != "Off" {
+		t.Errorf("Fullscreen = %+v, want Off", f)
+	}
+	if o := states["2"]; o.Origin != "unknown" || o.Source != "" {
+		t.Errorf("Output 2 = %+v, want unknown", o)
+	}
+	if w := doRequest(r, http.MethodPost, "/api/route", `{"routes":{"2":"3"}}`); w.Code != http.StatusOK {
+		t.Fatalf("route status = %d", w.Code)
+	}
+	if o := get()["2"]; o.Origin != "routed" || o.Input != vmix.Inputs.Input[2].Key {
+		t.Errorf("Output 2 = %+v, want routed to input 3", o)
+	}
+
+	// /api/inputs and /api/tally report the same routes.
+	for _, in := range inputsResponse() {
+		if in.Number == 3 && strings.Join(in.Outputs, ",") != "2,Overlay1" {
+			t.Errorf("/api/inputs outputs of input 3 = %v, want [2 Overlay1]", in.Outputs)
+		}
+	}
+	for _, tally := range tallyTable(vmix, vmixExtra) {
+		if tally.Number == 3 && strings.Join(tally.Outputs, ",") != "2,Overlay1" {
+			t.Errorf("/api/tally outputs of input 3 = %v, want [2 Overlay1]", tally.Outputs)
+		}
+	}
+
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// maxOverlayChannel is the number of vMix overlay channels controlled by OverlayInputN functions.
+const maxOverlayChannel = 4
+
+// overlayInput returns input number currently on overlay channel. 0 means off.
+func overlayInput(e *vMixExtra, channel int) uint {
+	for _, o := range e.Overlays.Overlay {
+		if int(o.Number) == channel {
+			return o.Input
+		}
+	}
+	return 0
+}
+
+// OverlayInputSet turns input on overlay channel on or off, only when it is not in the state already.
+// Current state is read from the latest refresh. empty input with on=false turns off whatever is on the channel.
+// changed reports whether a function was sent.
+func OverlayInputSet(v *vmixgo.Vmix, e *vMixExtra, channel int, input vmixgo.Input, on bool) (changed bool, err error) {
+	if channel < 1 || channel > maxOverlayChannel {
+		return false, fmt.Errorf("Overlay channel must be 1 to %d", maxOverlayChannel)
+	}
+	current := overlayInput(e, channel)
+	params := make(map[string]string)
+	if on {
+		if current != 0 && current == input.Number {
+			return false, nil
+		}
+		params["Input"] = input.Key
+		return true, sendFunction(v, fmt.Sprintf("OverlayInput%dIn", channel), params)
+	}
+	if current == 0 || (input.Key != "" && current != input.Number) {
+		return false, nil
+	}
+	return true, sendFunction(v, fmt.Sprintf("OverlayInput%dOut", channel), params)
+}
+
+// OverlayRequest Request JSON for SetOverlayHandler
+type OverlayRequest struct {
+	On    bool   `json:"on"`    // turn overlay on or off.
+	Input string `json:"input"` // input key, number or title. required when on.
+}
+
+// SetOverlayHandler sets overlay channel state idempotently for [POST] /api/overlays/:channel and returns resulting state.
+func SetOverlayHandler(c *gin.Context) {
+	channel, err := strconv.Atoi(c.Param("channel"))
+	if err != nil || channel < 1 || channel > maxOverlayChannel {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Overlay channel must be 1 to %d", maxOverlayChannel),
+		})
+		return
+	}
+	req := OverlayRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	input := vmixgo.Input{}
+	if req.Input != "" || req.On {
+		var ok bool
+		input, ok = findInput(req.Input)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Input not found",
+			})
+			return
+		}
+	}
+	vmix, vmixExtra := vmixSnapshot()
+	changed, err := OverlayInputSet(vmix, vmixExtra, channel, input, req.On)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if changed {
+		if err := refreshvMix(); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, overlayState(channel, changed))
+}
+
+// overlayState returns current state of overlay channel.
+func overlayState(channel int, changed bool) gin.H {
+	key := ""
+	vmix, vmixExtra := vmixSnapshot()
+	number := overlayInput(vmixExtra, channel)
+	for _, in := range vmix.Inputs.Input {
+		if number != 0 && in.Number == number {
+			key = in.Key
+		}
+	}
+	return gin.H{
+		"channel": channel,
+		"on":      number != 0,
+		"input":   key,
+		"changed": changed,
+	}
+}
+
+// OverlayLayout is input key on overlay channels saved by name. empty key means the channel is off.
+// vMix API has no overlay presets, so they are kept by this server.
+type OverlayLayout struct {
+	Name     string         `json:"name"`
+	Channels map[int]string `json:"channels"` // overlay channel to input key.
+}
+
+// Validate form
+func (l *OverlayLayout) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("Overlay preset name empty")
+	}
+	for channel := range l.Channels {
+		if channel < 1 || channel > maxOverlayChannel {
+			return fmt.Errorf("Overlay channel must be 1 to %d", maxOverlayChannel)
+		}
+	}
+	return nil
+}
+
+// overlayLayoutStore holds overlay layouts in memory only. They are lost on restart unless exported by /api/config/export .
+type overlayLayoutStore struct {
+	mu      sync.Mutex
+	layouts map[string]map[int]string
+}
+
+var overlayLayouts = &overlayLayoutStore{layouts: map[string]map[int]string{}}
+
+func (s *overlayLayoutStore) get(name string) (map[int]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	layout, ok := s.layouts[name]
+	return layout, ok
+}
+
+func (s *overlayLayoutStore) put(name string, layout map[int]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.layouts[name] = layout
+}
+
+// names returns sorted saved layout names.
+func (s *overlayLayoutStore) names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.layouts))
+	for name := range s.layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// list returns saved layouts ordered by name.
+func (s *overlayLayoutStore) list() []OverlayLayout {
+	names := s.names()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	layouts := make([]OverlayLayout, 0, len(names))
+	for _, name := range names {
+		layouts = append(layouts, OverlayLayout{Name: name, Channels: s.layouts[name]})
+	}
+	return layouts
+}
+
+// replace replaces all layouts by layouts.
+func (s *overlayLayoutStore) replace(layouts []OverlayLayout) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.layouts = map[string]map[int]string{}
+	for _, l := range layouts {
+		channels := map[int]string{}
+		for channel, key := range l.Channels {
+			channels[channel] = key
+		}
+		s.layouts[l.Name] = channels
+	}
+}
+
+// currentOverlayLayout returns input key on every overlay channel.
+func currentOverlayLayout() map[int]string {
+	layout := map[int]string{}
+	for channel := 1; channel <= maxOverlayChannel; channel++ {
+		layout[channel] = overlayState(channel, false)["input"].(string)
+	}
+	return layout
+}
+
+// overlayStates returns current state of every overlay channel.
+func overlayStates(changed map[int]bool) []gin.H {
+	states := make([]gin.H, 0, maxOverlayChannel)
+	for channel := 1; channel <= maxOverlayChannel; channel++ {
+		states = append(states, overlayState(channel, changed[channel]))
+	}
+	return states
+}
+
+// GetOverlayPresetHandler returns current overlay channels and saved layout names for [GET] /api/overlays/presets
+// and its alias /api/overlays/preset .
+func GetOverlayPresetHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"channels": overlayStates(nil),
+		"presets":  overlayLayouts.names(),
+	})
+}
+
+// SaveOverlayPresetHandler saves current overlay channels as layout for [PUT] /api/overlays/presets/:name .
+func SaveOverlayPresetHandler(c *gin.Context) {
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	overlayLayouts.put(c.Param("name"), currentOverlayLayout())
+	c.JSON(http.StatusOK, gin.H{
+		"name":     c.Param("name"),
+		"channels": overlayStates(nil),
+	})
+}
+
+// OverlayPresetRequest Request JSON for RecallOverlayPresetHandler
+type OverlayPresetRequest struct {
+	Name     string            `json:"name"`     // saved layout name.
+	Channels map[string]string `json:"channels"` // layout used if name is empty. channel to input key, number or title. "" turns channel off.
+}
+
+// RecallOverlayPresetHandler applies saved or given overlay layout for [POST] /api/overlays/presets
+// and its alias /api/overlays/preset , and returns resulting overlay channels. Channels not in the layout are left as is.
+// Every channel and input is validated before any function is sent.
+func RecallOverlayPresetHandler(c *gin.Context) {
+	req := OverlayPresetRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	layout := map[int]string{}
+	if req.Name != "" {
+		saved, ok := overlayLayouts.get(req.Name)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Overlay preset %q not found", req.Name),
+			})
+			return
+		}
+		layout = saved
+	} else {
+		for ch, input := range req.Channels {
+			channel, err := strconv.Atoi(ch)
+			if err != nil || channel < 1 || channel > maxOverlayChannel {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("Overlay channel must be 1 to %d", maxOverlayChannel),
+				})
+				return
+			}
+			layout[channel] = input
+		}
+	}
+	if len(layout) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "name or channels required",
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	inputs := map[int]vmixgo.Input{}
+	for channel, key := range layout {
+		if key == "" {
+			continue
+		}
+		input, ok := findInput(key)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Input %q not found", key),
+			})
+			return
+		}
+		inputs[channel] = input
+	}
+
+	changed := map[int]bool{}
+	vmix, vmixExtra := vmixSnapshot()
+	for channel := 1; channel <= maxOverlayChannel; channel++ {
+		key, ok := layout[channel]
+		if !ok {
+			continue
+		}
+		var err error
+		changed[channel], err = OverlayInputSet(vmix, vmixExtra, channel, inputs[channel], key != "")
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"channels": overlayStates(changed),
+	})
+}
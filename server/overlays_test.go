@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSetOverlayHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	// Lower Third is already on overlay 1.
+	w := doRequest(r, http.MethodPost, "/api/overlays/1", `{"on":true,"input":"3"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(s.Calls()) != 0 {
+		t.Errorf("function sent for overlay already on: %+v", s.Calls())
+	}
+
+	// Overlay 2 is off.
+	doRequest(r, http.MethodPost, "/api/overlays/2", `{"on":false}`)
+	if len(s.Calls()) != 0 {
+		t.Errorf("function sent for overlay already off: %+v", s.Calls())
+	}
+
+	doRequest(r, http.MethodPost, "/api/overlays/2", `{"on":true,"input":"CAM 1"}`)
+	doRequest(r, http.MethodPost, "/api/overlays/1", `{"on":false}`)
+	calls := s.Calls()
+	if len(calls) != 2 || calls[0].Function != "OverlayInput2In" || calls[1].Function != "OverlayInput1Out" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	if w := doRequest(r, http.MethodPost, "/api/overlays/9", `{"on":false}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestOverlayPresetHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPut, "/api/overlays/presets/lower-third", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	w = doRequest(r, http.MethodGet, "/api/overlays/presets", "")
+	if !strings.Contains(w.Body.String(), `"presets":["lower-third"]`) || !strings.Contains(w.Body.String(), `"channel":1,"input":"`+vmix.Inputs.Input[2].Key+`","on":true`) {
+		t.Errorf("unexpected response: %s", w.Body.String())
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/overlays/presets", `{"channels":{"1":"","2":"CAM 1"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 || calls[0].Function != "OverlayInput1Out" || calls[1].Function != "OverlayInput2In" || calls[1].Query.Get("Input") != vmix.Inputs.Input[0].Key {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	// saved layout matches current state, so nothing is sent.
+	s.Reset()
+	if w := doRequest(r, http.MethodPost, "/api/overlays/presets", `{"name":"lower-third"}`); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	// /overlays/preset is kept as an alias.
+	if w := doRequest(r, http.MethodGet, "/api/overlays/preset", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"lower-third"`) {
+		t.Errorf("alias GET: %d %s", w.Code, w.Body.String())
+	}
+	if w := doRequest(r, http.MethodPost, "/api/overlays/preset", `{"name":"lower-third"}`); w.Code != http.StatusOK {
+		t.Errorf("alias POST: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	for body, want := range map[string]int{
+		`{"name":"missing"}`:           http.StatusNotFound,
+		`{"channels":{"5":"1"}}`:       http.StatusBadRequest,
+		`{"channels":{"1":"missing"}}`: http.StatusNotFound,
+		`{}`:                           http.StatusBadRequest,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/overlays/presets", body); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+	if calls := s.Calls(); len(calls) != 0 {
+		t.Errorf("functions sent despite invalid request: %+v", calls)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRestartPlayHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/inputs/opener.mp4/restart-play", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 || calls[0].Function != "Restart" || calls[1].Function != "Play" {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	if calls[1].Query.Get("Input") != vmix.Inputs.Input[1].Key {
+		t.Errorf("Input = %s, want %s", calls[1].Query.Get("Input"), vmix.Inputs.Input[1].Key)
+	}
+}
+
+func TestPlayAllHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/playback/all/play", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	// only opener.mp4 is a paused video. paused title and running camera are untouched.
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "Play" || calls[0].Query.Get("Input") != vmix.Inputs.Input[1].Key {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	s.Reset()
+	doRequest(r, http.MethodPost, "/api/playback/all/pause", "")
+	if len(s.Calls()) != 0 {
+		t.Errorf("unexpected calls: %+v", s.Calls())
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// SetCrop crops input. values are 0 to 1, X1,Y1 is top-left and X2,Y2 is bottom-right.
+func SetCrop(v *vmixgo.Vmix, input string, x1, y1, x2, y2 float64) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = fmt.Sprintf("%s,%s,%s,%s", formatFloat(x1), formatFloat(y1), formatFloat(x2), formatFloat(y2))
+	return sendFunction(v, "SetCrop", params)
+}
+
+func setCropEdge(v *vmixgo.Vmix, function string, input string, value float64) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = formatFloat(value)
+	return sendFunction(v, function, params)
+}
+
+// SetCropX1 sets left crop of input. 0 to 1.
+func SetCropX1(v *vmixgo.Vmix, input string, value float64) error {
+	return setCropEdge(v, "SetCropX1", input, value)
+}
+
+// SetCropX2 sets right crop of input. 0 to 1.
+func SetCropX2(v *vmixgo.Vmix, input string, value float64) error {
+	return setCropEdge(v, "SetCropX2", input, value)
+}
+
+// SetCropY1 sets top crop of input. 0 to 1.
+func SetCropY1(v *vmixgo.Vmix, input string, value float64) error {
+	return setCropEdge(v, "SetCropY1", input, value)
+}
+
+// SetCropY2 sets bottom crop of input. 0 to 1.
+func SetCropY2(v *vmixgo.Vmix, input string, value float64) error {
+	return setCropEdge(v, "SetCropY2", input, value)
+}
+
+// SetCropRequest Request JSON for SetCropHandler
+type SetCropRequest struct {
+	X1 float64 `json:"x1"` // left. 0 to 1.
+	Y1 float64 `json:"y1"` // top. 0 to 1.
+	X2 float64 `json:"x2"` // right. 0 to 1.
+	Y2 float64 `json:"y2"` // bottom. 0 to 1.
+}
+
+// Validate form
+func (r *SetCropRequest) Validate() error {
+	for _, f := range []float64{r.X1, r.Y1, r.X2, r.Y2} {
+		if f < 0 || f > 1 {
+			return fmt.Errorf("Crop values must be 0 to 1")
+		}
+	}
+	if r.X1 >= r.X2 {
+		return fmt.Errorf("x1 must be less than x2")
+	}
+	if r.Y1 >= r.Y2 {
+		return fmt.Errorf("y1 must be less than y2")
+	}
+	return nil
+}
+
+// SetCropHandler crops input for [POST] /api/inputs/:key/crop .
+func SetCropHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	req := SetCropRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := SetCrop(vmix, input.Key, req.X1, req.Y1, req.X2, req.Y2); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, req)
+}
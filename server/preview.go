@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// PreviewInput sends input to Preview.
+func PreviewInput(v *vmixgo.Vmix, input string) error {
+	return sendInputFunction(v, "PreviewInput", input)
+}
+
+// PreviewInputNext sends next input to Preview.
+func PreviewInputNext(v *vmixgo.Vmix) error {
+	return sendFunction(v, "PreviewInputNext", nil)
+}
+
+// PreviewInputPrevious sends previous input to Preview.
+func PreviewInputPrevious(v *vmixgo.Vmix) error {
+	return sendFunction(v, "PreviewInputPrevious", nil)
+}
+
+// ActiveInput cuts input to Program.
+func ActiveInput(v *vmixgo.Vmix, input string) error {
+	return sendInputFunction(v, "ActiveInput", input)
+}
+
+// NextInput cuts next input to Program.
+func NextInput(v *vmixgo.Vmix) error {
+	return sendFunction(v, "NextInput", nil)
+}
+
+// PreviousInput cuts previous input to Program.
+func PreviousInput(v *vmixgo.Vmix) error {
+	return sendFunction(v, "PreviousInput", nil)
+}
+
+// stepPreview sends step function and responds with the resulting Preview input.
+func stepPreview(c *gin.Context, step func(v *vmixgo.Vmix) error) {
+	vmix, _ := vmixSnapshot()
+	if err := step(vmix); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ = vmixSnapshot()
+	res := gin.H{"preview": vmix.Preview}
+	for _, in := range vmix.Inputs.Input {
+		if in.Number == vmix.Preview {
+			res["input"] = in.Key
+			res["title"] = in.Title
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// PreviewNextHandler sends next input to Preview for [POST] /api/preview/next .
+func PreviewNextHandler(c *gin.Context) {
+	stepPreview(c, PreviewInputNext)
+}
+
+// PreviewPreviousHandler sends previous input to Preview for [POST] /api/preview/previous .
+func PreviewPreviousHandler(c *gin.Context) {
+	stepPreview(c, PreviewInputPrevious)
+}
+
+// SwitcherRequest Request JSON for SwitcherHandler
+type SwitcherRequest struct {
+	Preview    string `json:"preview"`    // input key, number or title to load on Preview.
+	Program    string `json:"program"`    // input key, number or title to take to Program.
+	Transition string `json:"transition"` // transition effect. defaults to "Cut".
+	DurationMs uint   `json:"durationMs"` // transition duration in milliseconds. defaults to 500.
+}
+
+// SwitcherHandler takes program input and loads the next shot on Preview for [POST] /api/switcher ,
+// and returns the resulting active and preview inputs.
+// Program is taken first, as vMix moves the previous Program input to Preview after a transition.
+func SwitcherHandler(c *gin.Context) {
+	req := SwitcherRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if req.Transition == "" {
+		req.Transition = "Cut"
+	}
+	takesDuration, ok := transitionEffects[req.Transition]
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unknown transition %q. valid effects: %s", req.Transition, strings.Join(transitionEffectNames(), ", ")),
+		})
+		return
+	}
+	program, ok := findInput(req.Program)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Program input %q not found", req.Program),
+		})
+		return
+	}
+	preview, ok := findInput(req.Preview)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Preview input %q not found", req.Preview),
+		})
+		return
+	}
+
+	params := make(map[string]string)
+	params["Input"] = program.Key
+	if takesDuration {
+		duration := req.DurationMs
+		if duration == 0 {
+			duration = defaultTransitionDuration
+		}
+		params["Duration"] = strconv.Itoa(int(duration))
+	}
+	vmix, _ := vmixSnapshot()
+	err := sendFunction(vmix, req.Transition, params)
+	if err == nil {
+		transitionRunning.started(req.Transition, params["Duration"])
+		err = PreviewInput(vmix, preview.Key)
+	}
+	if err == nil {
+		err = refreshvMix()
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ = vmixSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"active":  vmix.Active,
+		"preview": vmix.Preview,
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestPreviewStepHandlers(t *testing.T) {
+	s, r := setupTest(t)
+	for path, function := range map[string]string{
+		"/api/preview/next":     "PreviewInputNext",
+		"/api/preview/previous": "PreviewInputPrevious",
+	} {
+		s.Reset()
+		w := doRequest(r, http.MethodPost, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d: %s", path, w.Code, http.StatusOK, w.Body.String())
+		}
+		if calls := s.Calls(); len(calls) != 1 || calls[0].Function != function {
+			t.Errorf("%s: unexpected calls: %+v", path, calls)
+		}
+		var body struct {
+			Preview uint   `json:"preview"`
+			Input   string `json:"input"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Preview != 2 || body.Input != vmix.Inputs.Input[1].Key {
+			t.Errorf("%s: resulting preview = %+v, want input 2", path, body)
+		}
+	}
+}
+
+func TestSwitcherHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/switcher", `{"program":"3","preview":"CAM 1","transition":"Fade","durationMs":800}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Function != "Fade" || calls[0].Query.Get("Input") != vmix.Inputs.Input[2].Key || calls[0].Query.Get("Duration") != "800" {
+		t.Errorf("unexpected call: %v", calls[0].Query)
+	}
+	if calls[1].Function != "PreviewInput" || calls[1].Query.Get("Input") != vmix.Inputs.Input[0].Key {
+		t.Errorf("unexpected call: %v", calls[1].Query)
+	}
+	if w.Body.String() != `{"active":1,"preview":2}` {
+		t.Errorf("unexpected response: %s", w.Body.String())
+	}
+
+	s.Reset()
+	for body, want := range map[string]int{
+		`{"program":"1","preview":"missing"}`:               http.StatusNotFound,
+		`{"program":"missing","preview":"1"}`:               http.StatusNotFound,
+		`{"program":"1","preview":"2","transition":"Spin"}`: http.StatusBadRequest,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/switcher", body); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid request")
+	}
+}
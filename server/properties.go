@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// InputProperty is an editable property of input for generic property panels.
+type InputProperty struct {
+	Name     string      `json:"name"`              // property name. e.g. "audio.volume" .
+	Group    string      `json:"group"`             // "audio", "colour", "crop", "position", "playback" or "fields" .
+	Type     string      `json:"type"`              // "number", "bool", "text", "image" or "set" .
+	Value    interface{} `json:"value"`             // current value. null if vMix XML does not report it.
+	Min      *float64    `json:"min,omitempty"`     // minimum for number.
+	Max      *float64    `json:"max,omitempty"`     // maximum for number.
+	Options  []string    `json:"options,omitempty"` // members for set.
+	Function string      `json:"function"`          // vMix function to edit. "On/Off" pairs are separated by "/" .
+}
+
+// numberProperty returns number InputProperty with range.
+func numberProperty(name, group string, value interface{}, min, max float64, function string) InputProperty {
+	return InputProperty{Name: name, Group: group, Type: "number", Value: value, Min: &min, Max: &max, Function: function}
+}
+
+// parseFloatOrNil parses s as float. empty or invalid s returns nil.
+func parseFloatOrNil(s string) interface{} {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// inputProperties describes editable properties of input with current values.
+func inputProperties(input vmixgo.Input, e InputExtra, extra *vMixExtra) []InputProperty {
+	props := []InputProperty{}
+
+	if input.AudioBusses != "" {
+		buses := []string{}
+		for _, b := range extra.Audio.Bus {
+			buses = append(buses, b.Name())
+		}
+		assigned := []string{}
+		for _, b := range buses {
+			if inputBuses(input.AudioBusses)[b] {
+				assigned = append(assigned, b)
+			}
+		}
+		props = append(props,
+			numberProperty("audio.volume", "audio", input.Volume, 0, 100, "SetVolume"),
+			numberProperty("audio.balance", "audio", input.Balance, -1, 1, "SetBalance"),
+			numberProperty("audio.gain", "audio", parseFloatOrNil(input.GainDb), 0, 24, "SetGain"),
+			InputProperty{Name: "audio.muted", Group: "audio", Type: "bool", Value: input.Muted, Function: "AudioOff/AudioOn"},
+			InputProperty{Name: "audio.buses", Group: "audio", Type: "set", Value: assigned, Options: buses, Function: "AudioBusOn/AudioBusOff"},
+		)
+	}
+
+	if input.Duration > 0 {
+		props = append(props,
+			numberProperty("playback.position", "playback", input.AttrPosition, 0, float64(input.Duration), "SetPosition"),
+			InputProperty{Name: "playback.loop", Group: "playback", Type: "bool", Value: input.Loop, Function: "LoopOn/LoopOff"},
+		)
+	}
+
+	props = append(props,
+		numberProperty("position.pan_x", "position", parseFloatOrNil(input.Position.PanX), -2, 2, "SetPanX"),
+		numberProperty("position.pan_y", "position", parseFloatOrNil(input.Position.PanY), -2, 2, "SetPanY"),
+		numberProperty("position.zoom", "position", parseFloatOrNil(input.Position.ZoomX), 0, 5, "SetZoom"),
+	)
+
+	// vMix XML reports crop only in newer versions.
+	for _, edge := range []struct{ name, value, function string }{
+		{"x1", e.Position.CropX1, "SetCropX1"},
+		{"y1", e.Position.CropY1, "SetCropY1"},
+		{"x2", e.Position.CropX2, "SetCropX2"},
+		{"y2", e.Position.CropY2, "SetCropY2"},
+	} {
+		props = append(props, numberProperty("crop."+edge.name, "crop", parseFloatOrNil(edge.value), 0, 1, edge.function))
+	}
+
+	// vMix XML does not report colour correction values.
+	names := make([]string, 0, len(colourFunctions))
+	for name := range colourFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := colourFunctions[name]
+		props = append(props, numberProperty("colour."+name, "colour", nil, f.Min, f.Max, f.Function))
+	}
+
+	for _, t := range e.Text {
+		props = append(props, InputProperty{Name: "fields." + t.Name, Group: "fields", Type: "text", Value: t.Value, Function: "SetText"})
+	}
+	for _, i := range e.Image {
+		props = append(props, InputProperty{Name: "fields." + i.Name, Group: "fields", Type: "image", Value: i.Value, Function: "SetImage"})
+	}
+	return props
+}
+
+// GetInputPropertiesHandler returns editable properties of input with current values and ranges
+// for [GET] /api/inputs/:key/properties .
+func GetInputPropertiesHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	_, vmixExtra := vmixSnapshot()
+	e, _ := vmixExtra.input(input.Key)
+	c.JSON(http.StatusOK, gin.H{
+		"input":      input.Key,
+		"title":      input.Title,
+		"type":       input.SceneType,
+		"properties": inputProperties(input, e, vmixExtra),
+	})
+}
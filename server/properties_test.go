@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGetInputPropertiesHandler(t *testing.T) {
+	_, r := setupTest(t)
+
+	props := func(key string) map[string]InputProperty {
+		w := doRequest(r, http.MethodGet, "/api/inputs/"+key+"/properties", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		res := struct {
+			Properties []InputProperty `json:"properties"`
+		}{}
+		json.Unmarshal(w.Body.Bytes(), &res)
+		m := map[string]InputProperty{}
+		for _, p := range res.Properties {
+			m[p.Name] = p
+		}
+		return m
+	}
+
+	video := props("2")
+	if p := video["audio.volume"]; p.Value != 80.0 || *p.Max != 100 || p.Function != "SetVolume" {
+		t.Errorf("unexpected audio.volume: %+v", p)
+	}
+	if p := video["audio.buses"]; len(p.Options) != 2 {
+		t.Errorf("unexpected audio.buses: %+v", p)
+	}
+	if p := video["playback.position"]; *p.Max != 15000 {
+		t.Errorf("unexpected playback.position: %+v", p)
+	}
+	if p := video["colour.hue"]; p.Value != nil || *p.Min != -180 {
+		t.Errorf("unexpected colour.hue: %+v", p)
+	}
+
+	title := props("3")
+	if p := title["fields.Headline.Text"]; p.Type != "text" || p.Value != "Hello" {
+		t.Errorf("unexpected field: %+v", p)
+	}
+	if _, ok := title["audio.volume"]; ok {
+		t.Error("title without audio has audio properties")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// ScriptStart starts vMix script by name.
+func ScriptStart(v *vmixgo.Vmix, name string) error {
+	params := make(map[string]string)
+	params["Value"] = name
+	return sendFunction(v, "ScriptStart", params)
+}
+
+// maxScriptParams is the number of vMix dynamic values, DynamicValue1 to DynamicValue4.
+const maxScriptParams = 4
+
+// ScriptStartWithParams starts vMix script by name with params.
+// vMix scripts take no arguments, so params are set to DynamicValue1 to DynamicValue4 in order before starting,
+// and the script reads them from <dynamic> of API.XML(). Other dynamic values are left unchanged.
+func ScriptStartWithParams(v *vmixgo.Vmix, name string, params []string) error {
+	if len(params) > maxScriptParams {
+		return fmt.Errorf("At most %d params are allowed", maxScriptParams)
+	}
+	for i, p := range params {
+		q := make(map[string]string)
+		q["Value"] = p
+		if err := sendFunction(v, fmt.Sprintf("SetDynamicValue%d", i+1), q); err != nil {
+			return err
+		}
+	}
+	return ScriptStart(v, name)
+}
+
+// ScriptStop stops vMix script by name.
+func ScriptStop(v *vmixgo.Vmix, name string) error {
+	params := make(map[string]string)
+	params["Value"] = name
+	return sendFunction(v, "ScriptStop", params)
+}
+
+// ScriptStopAll stops all running vMix scripts.
+func ScriptStopAll(v *vmixgo.Vmix) error {
+	return sendFunction(v, "ScriptStopAll", nil)
+}
+
+// StartScriptRequest Request JSON for StartScriptHandler
+type StartScriptRequest struct {
+	Params []string `json:"params"` // optional script params set to DynamicValue1 to DynamicValue4.
+}
+
+// StartScriptHandler starts script for [POST] /api/scripts/:name/start .
+// Request body is optional.
+func StartScriptHandler(c *gin.Context) {
+	req := StartScriptRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if len(req.Params) > maxScriptParams {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("At most %d params are allowed", maxScriptParams),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := ScriptStartWithParams(vmix, c.Param("name"), req.Params); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"script": c.Param("name"),
+	})
+}
+
+// StopScriptHandler stops script for [POST] /api/scripts/:name/stop .
+func StopScriptHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
+	if err := ScriptStop(vmix, c.Param("name")); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"script": c.Param("name"),
+	})
+}
+
+// StopAllScriptsHandler stops all scripts for [POST] /api/scripts/stopall .
+func StopAllScriptsHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
+	if err := ScriptStopAll(vmix); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestStartScriptHandler(t *testing.T) {
+	s, r := setupTest(t)
+
+	for body, want := range map[string][][2]string{
+		``:   {{"ScriptStart", "intro"}},
+		`{}`: {{"ScriptStart", "intro"}},
+		`{"params":["CAM 1","5,000"]}`: {
+			{"SetDynamicValue1", "CAM 1"},
+			{"SetDynamicValue2", "5,000"},
+			{"ScriptStart", "intro"},
+		},
+	} {
+		s.Reset()
+		if w := doRequest(r, http.MethodPost, "/api/scripts/intro/start", body); w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", body, w.Code, w.Body.String())
+		}
+		calls := s.Calls()
+		if len(calls) != len(want) {
+			t.Fatalf("%s: unexpected calls: %+v", body, calls)
+		}
+		for i, c := range calls {
+			if c.Function != want[i][0] || c.Query.Get("Value") != want[i][1] {
+				t.Errorf("%s: call %d = %s %s, want %s %s", body, i, c.Function, c.Query.Get("Value"), want[i][0], want[i][1])
+			}
+		}
+	}
+
+	s.Reset()
+	if w := doRequest(r, http.MethodPost, "/api/scripts/intro/start", `{"params":["1","2","3","4","5"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite too many params")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// Snapshot saves snapshot of Output to filename on vMix machine.
+func Snapshot(v *vmixgo.Vmix, filename string) error {
+	params := make(map[string]string)
+	params["Value"] = filename
+	return sendFunction(v, "Snapshot", params)
+}
+
+// SnapshotInput saves snapshot of input to filename on vMix machine.
+func SnapshotInput(v *vmixgo.Vmix, input string, filename string) error {
+	params := make(map[string]string)
+	params["Input"] = input
+	params["Value"] = filename
+	return sendFunction(v, "SnapshotInput", params)
+}
+
+// windowsAbsPath matches absolute Windows paths such as `C:\snapshots` or `\\server\share` .
+var windowsAbsPath = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\)`)
+
+// isAbsPath reports whether path is absolute on vMix machine, which is usually Windows.
+func isAbsPath(path string) bool {
+	return windowsAbsPath.MatchString(path) || filepath.IsAbs(path)
+}
+
+// snapshotPath resolves snapshot file path. path ending with separator or without extension is a directory,
+// and a file name is generated in it.
+func snapshotPath(path string, now time.Time) string {
+	if strings.HasSuffix(path, `\`) || strings.HasSuffix(path, "/") {
+		return path + now.Format("snapshot_20060102_150405.png")
+	}
+	if filepath.Ext(path) == "" {
+		sep := `\`
+		if strings.Contains(path, "/") {
+			sep = "/"
+		}
+		return path + sep + now.Format("snapshot_20060102_150405.png")
+	}
+	return path
+}
+
+// SnapshotRequest Request JSON for SnapshotHandler
+type SnapshotRequest struct {
+	Path  string `json:"path"`  // directory or file path on vMix machine.
+	Input string `json:"input"` // input key, number or title. empty takes Output.
+}
+
+// SnapshotHandler saves snapshot for [POST] /api/snapshot and returns resolved path.
+// Relative paths are sent but warned, as vMix resolves them against its own working directory.
+// The path is on vMix machine, so whether the file was written is not checked.
+func SnapshotHandler(c *gin.Context) {
+	req := SnapshotRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if strings.TrimSpace(req.Path) == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "Path empty",
+		})
+		return
+	}
+	path := snapshotPath(req.Path, time.Now())
+	res := gin.H{
+		"path": path,
+		"ok":   false,
+	}
+	if !isAbsPath(req.Path) {
+		res["warning"] = fmt.Sprintf("%q is not an absolute path. vMix resolves it against its own working directory", req.Path)
+	}
+
+	var err error
+	vmix, _ := vmixSnapshot()
+	if req.Input != "" {
+		input, ok := findInput(req.Input)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Input not found",
+			})
+			return
+		}
+		err = SnapshotInput(vmix, input.Key, path)
+	} else {
+		err = Snapshot(vmix, path)
+	}
+	if err != nil {
+		res["error"] = err.Error()
+		c.JSON(http.StatusInternalServerError, res)
+		return
+	}
+	res["ok"] = true
+	c.JSON(http.StatusOK, res)
+}
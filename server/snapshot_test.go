@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotPath(t *testing.T) {
+	now := time.Date(2021, 4, 1, 12, 30, 0, 0, time.UTC)
+	for path, want := range map[string]string{
+		`C:\snapshots\`:        `C:\snapshots\snapshot_20210401_123000.png`,
+		`C:\snapshots`:         `C:\snapshots\snapshot_20210401_123000.png`,
+		`C:\snapshots\cam.jpg`: `C:\snapshots\cam.jpg`,
+		`/tmp/snapshots`:       `/tmp/snapshots/snapshot_20210401_123000.png`,
+	} {
+		if got := snapshotPath(path, now); got != want {
+			t.Errorf("snapshotPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+	for path, want := range map[string]bool{
+		`C:\snapshots`:      true,
+		`\\server\share`:    true,
+		`/tmp`:              true,
+		`snapshots\cam.png`: false,
+	} {
+		if got := isAbsPath(path); got != want {
+			t.Errorf("isAbsPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	s, r := setupTest(t)
+	dir := t.TempDir()
+
+	w := doRequest(r, http.MethodPost, "/api/snapshot", `{"path":"`+dir+`","input":"1"}`)
+	res := struct {
+		Path    string `json:"path"`
+		OK      bool   `json:"ok"`
+		Warning string `json:"warning"`
+	}{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if w.Code != http.StatusOK || !res.OK || !strings.HasPrefix(res.Path, dir+"/snapshot_") || res.Warning != "" {
+		t.Errorf("unexpected response: %s", w.Body.String())
+	}
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "SnapshotInput" || calls[0].Query.Get("Value") != res.Path {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/snapshot", `{"path":"snapshots\\cam.png"}`)
+	if !strings.Contains(w.Body.String(), "not an absolute path") || strings.Contains(w.Body.String(), "file_found") {
+		t.Errorf("relative path not warned: %s", w.Body.String())
+	}
+
+	s.SetFunctionResponse(http.StatusOK, "Function Failed")
+	if w := doRequest(r, http.MethodPost, "/api/snapshot", `{"path":"C:\\snapshots\\"}`); w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"ok":false`) {
+		t.Errorf("failure not reported: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// Tally is tally state of an input across every mix and output.
+type Tally struct {
+	Input        string   `json:"input"`         // input key.
+	Number       uint     `json:"number"`        // input number.
+	Title        string   `json:"title"`         // input title.
+	Program      bool     `json:"program"`       // on Output of main mix or an overlay channel.
+	Preview      bool     `json:"preview"`       // on Preview of main mix and not on Program.
+	Mixes        []uint   `json:"mixes"`         // mix numbers the input is active on.
+	PreviewMixes []uint   `json:"preview_mixes"` // mix numbers the input is on preview of.
+	Outputs      []string `json:"outputs"`       // outputs and overlay channels showing the input. e.g. ["Program","2","Overlay1"] .
+}
+
+// tallyTable returns tally of every input in input order.
+// Outputs are resolved by inputOutputs, the same as /api/inputs and /api/outputs .
+func tallyTable(v *vmixgo.Vmix, e *vMixExtra) []Tally {
+	allMixes := mixes(v, e)
+	states := outputStates(v, e)
+	table := make([]Tally, 0, len(v.Inputs.Input))
+	for _, in := range v.Inputs.Input {
+		t := Tally{Input: in.Key, Number: in.Number, Title: in.Title, Mixes: []uint{}, PreviewMixes: []uint{}, Outputs: inputOutputs(v, e, states, in)}
+		for _, m := range allMixes {
+			if m.Active == in.Number {
+				t.Mixes = append(t.Mixes, m.Number)
+			}
+			if m.Preview == in.Number {
+				t.PreviewMixes = append(t.PreviewMixes, m.Number)
+			}
+		}
+		for _, o := range e.Overlays.Overlay {
+			if o.Input == in.Number && in.Number != 0 {
+				t.Program = true
+			}
+		}
+		if in.Number == v.Active {
+			t.Program = true
+		}
+		t.Preview = in.Number == v.Preview && !t.Program
+		table = append(table, t)
+	}
+	return table
+}
+
+// tallyChanged reports whether tally of any input differs between prev and next.
+func tallyChanged(prev, next *vmixgo.Vmix, prevExtra, nextExtra *vMixExtra) bool {
+	return !reflect.DeepEqual(tallyTable(prev, prevExtra), tallyTable(next, nextExtra))
+}
+
+// GetTallyHandler returns tally of every input across mixes and outputs for [GET] /api/tally .
+func GetTallyHandler(c *gin.Context) {
+	vmix, vmixExtra := vmixSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"tally": tallyTable(vmix, vmixExtra),
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestGetTallyHandler(t *testing.T) {
+	_, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/tally", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body struct {
+		Tally []Tally `json:"tally"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Tally) != 3 {
+		t.Fatalf("unexpected tally: %+v", body.Tally)
+	}
+	keys := vmix.Inputs.Input
+	want := []Tally{
+		{Input: keys[0].Key, Number: 1, Title: keys[0].Title, Program: true, Mixes: []uint{1}, PreviewMixes: []uint{2}, Outputs: []string{"Program"}},
+		{Input: keys[1].Key, Number: 2, Title: keys[1].Title, Preview: true, Mixes: []uint{}, PreviewMixes: []uint{1}, Outputs: []string{}},
+		{Input: keys[2].Key, Number: 3, Title: keys[2].Title, Program: true, Mixes: []uint{2}, PreviewMixes: []uint{}, Outputs: []string{"Overlay1"}},
+	}
+	if !reflect.DeepEqual(body.Tally, want) {
+		t.Errorf("tally = %+v, want %+v", body.Tally, want)
+	}
+}
+
+func TestWebhookEventsBetweenTallyChanged(t *testing.T) {
+	s, _ := setupTest(t)
+	prev, prevExtra := vmix, vmixExtra
+	if events := webhookEventsBetween(prev, vmix, prevExtra, vmixExtra); len(events) != 0 {
+		t.Errorf("unexpected events without change: %+v", events)
+	}
+
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<active>1</active>", "<active>2</active>", 1))
+	if err := fetchvMix(); err != nil {
+		t.Fatal(err)
+	}
+	var tally *WebhookEvent
+	for _, e := range webhookEventsBetween(prev, vmix, prevExtra, vmixExtra) {
+		if e.Event == EventTallyChanged {
+			e := e
+			tally = &e
+		}
+	}
+	if tally == nil {
+		t.Fatal("tally_changed not fired")
+	}
+	if len(tally.Tally) != 3 || !tally.Tally[1].Program || tally.Tally[0].Program {
+		t.Errorf("unexpected tally: %+v", tally.Tally)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// defaultThumbnailTTL is how long a thumbnail is served from cache while input looks unchanged.
+const defaultThumbnailTTL = 10 * time.Second
+
+// thumbnail settings
+var (
+	thumbnailWait     = 3 * time.Second       // how long to wait for vMix to write snapshot file
+	thumbnailInterval = 50 * time.Millisecond // snapshot file polling interval
+)
+
+// thumbnail is a cached input snapshot.
+type thumbnail struct {
+	png       []byte
+	frame     int       // incremented on every new snapshot. used for ETag.
+	signature string    // inputSignature when snapshot was taken.
+	taken     time.Time // when snapshot was taken.
+}
+
+// thumbnailCache caches input snapshots so a wall of auto-refreshing thumbnails does not hammer vMix.
+type thumbnailCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	thumbs map[string]*thumbnail
+	// locks serializes snapshots of the same input.
+	locks map[string]*sync.Mutex
+}
+
+var thumbnails = &thumbnailCache{
+	ttl:    defaultThumbnailTTL,
+	thumbs: map[string]*thumbnail{},
+	locks:  map[string]*sync.Mutex{},
+}
+
+// inputSignature summarizes input state which changes when its frame changes.
+// Running inputs such as cameras keep the same signature, so they are re-taken after ttl.
+func inputSignature(input vmixgo.Input, e InputExtra) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s/%d", input.State, input.AttrPosition)
+	for _, f := range e.Fields() {
+		fmt.Fprintf(b, "/%s", f.Value)
+	}
+	return b.String()
+}
+
+// lock returns mutex of input key.
+func (c *thumbnailCache) lock(key string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[key] = l
+	}
+	return l
+}
+
+// get returns cached thumbnail of input if it is still fresh, otherwise takes snapshot by take.
+func (c *thumbnailCache) get(input vmixgo.Input, signature string, take func(frame int) ([]byte, error)) (*thumbnail, error) {
+	l := c.lock(input.Key)
+	l.Lock()
+	defer l.Unlock()
+
+	c.mu.Lock()
+	cached, ttl := c.thumbs[input.Key], c.ttl
+	c.mu.Unlock()
+	if cached != nil && cached.signature == signature && time.Since(cached.taken) < ttl {
+		return cached, nil
+	}
+	frame := 1
+	if cached != nil {
+		frame = cached.frame + 1
+	}
+	png, err := take(frame)
+	if err != nil {
+		return nil, err
+	}
+	t := &thumbnail{png: png, frame: frame, signature: signature, taken: time.Now()}
+	c.mu.Lock()
+	c.thumbs[input.Key] = t
+	c.mu.Unlock()
+	return t, nil
+}
+
+// prune drops thumbnails and locks of inputs no longer in v, so removed inputs do not keep their PNGs in memory.
+func (c *thumbnailCache) prune(v *vmixgo.Vmix) {
+	keys := make(map[string]bool, len(v.Inputs.Input))
+	for _, in := range v.Inputs.Input {
+		keys[in.Key] = true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.thumbs {
+		if !keys[key] {
+			delete(c.thumbs, key)
+		}
+	}
+	for key := range c.locks {
+		if !keys[key] {
+			delete(c.locks, key)
+		}
+	}
+}
+
+// takeSnapshot asks vMix to save snapshot into -upload-dir and reads it.
+// vMix writes the file asynchronously, so it is polled until thumbnailWait.
+func takeSnapshot(key string, frame int) ([]byte, error) {
+	dir := filepath.Join(*uploadDir, "thumbnails")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(filepath.Join(dir, fmt.Sprintf("%s_%d.png", key, frame)))
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+	defer os.Remove(path)
+	vmix, _ := vmixSnapshot()
+	if err := SnapshotInput(vmix, key, path); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(thumbnailWait)
+	for {
+		b, err := ioutil.ReadFile(path)
+		if err == nil && len(b) > 0 && http.DetectContentType(b) == "image/png" {
+			return b, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("vMix did not write snapshot %s. vMix and this server must share the file system", path)
+		}
+		time.Sleep(thumbnailInterval)
+	}
+}
+
+// GetThumbnailHandler returns PNG snapshot of input for [GET] /api/inputs/:key/thumbnail .
+// Snapshot is cached for -thumbnail-ttl unless input state, position or title fields change.
+// ETag is input key and frame counter, so If-None-Match returns 304 until a new snapshot is taken.
+func GetThumbnailHandler(c *gin.Context) {
+	input, ok := findInput(c.Param("key"))
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	_, vmixExtra := vmixSnapshot()
+	e, _ := vmixExtra.input(input.Key)
+	t, err := thumbnails.get(input, inputSignature(input, e), func(frame int) ([]byte, error) {
+		return takeSnapshot(input.Key, frame)
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	etag := fmt.Sprintf(`"%s-%d"`, input.Key, t.frame)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", t.png)
+}
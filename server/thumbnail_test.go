@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+func TestThumbnailCache(t *testing.T) {
+	setupTest(t)
+	c := &thumbnailCache{ttl: time.Minute, thumbs: map[string]*thumbnail{}, locks: map[string]*sync.Mutex{}}
+	input := vmix.Inputs.Input[0]
+	taken := 0
+	take := func(frame int) ([]byte, error) {
+		taken++
+		return []byte{byte(frame)}, nil
+	}
+
+	for i, tt := range []struct {
+		signature string
+		frame     int
+	}{
+		{"Running/0", 1},
+		{"Running/0", 1},
+		{"Paused/0", 2},
+	} {
+		th, err := c.get(input, tt.signature, take)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if th.frame != tt.frame {
+			t.Errorf("#%d frame = %d, want %d", i, th.frame, tt.frame)
+		}
+	}
+	if taken != 2 {
+		t.Errorf("taken %d snapshots, want 2", taken)
+	}
+
+	c.ttl = 0
+	if th, _ := c.get(input, "Paused/0", take); th.frame != 3 {
+		t.Errorf("expired thumbnail not taken again: frame %d", th.frame)
+	}
+}
+
+func TestThumbnailCachePrune(t *testing.T) {
+	setupTest(t)
+	thumbnails.thumbs = map[string]*thumbnail{}
+	key := vmix.Inputs.Input[0].Key
+	thumbnails.get(vmix.Inputs.Input[0], "", func(int) ([]byte, error) { return []byte("png"), nil })
+	thumbnails.get(vmixgo.Input{Key: "removed"}, "", func(int) ([]byte, error) { return []byte("png"), nil })
+
+	if err := refreshvMix(); err != nil {
+		t.Fatal(err)
+	}
+	thumbnails.mu.Lock()
+	defer thumbnails.mu.Unlock()
+	if _, ok := thumbnails.thumbs["removed"]; ok {
+		t.Error("thumbnail of removed input kept")
+	}
+	if _, ok := thumbnails.locks["removed"]; ok {
+		t.Error("lock of removed input kept")
+	}
+	if _, ok := thumbnails.thumbs[key]; !ok {
+		t.Error("thumbnail of existing input pruned")
+	}
+}
+
+func TestGetThumbnailHandler(t *testing.T) {
+	s, r := setupTest(t)
+	*uploadDir = t.TempDir()
+	thumbnails.thumbs = map[string]*thumbnail{}
+
+	img := &bytes.Buffer{}
+	png.Encode(img, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	// vMix writes the snapshot file asynchronously.
+	go func() {
+		for i := 0; i < 100; i++ {
+			if calls := s.Calls(); len(calls) > 0 {
+				ioutil.WriteFile(calls[0].Query.Get("Value"), img.Bytes(), 0644)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	w := doRequest(r, http.MethodGet, "/api/inputs/1/thumbnail", "")
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), img.Bytes()) {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "SnapshotInput" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/inputs/1/thumbnail", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	if calls := s.Calls(); len(calls) != 1 {
+		t.Errorf("cached thumbnail taken again: %+v", calls)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultFunctionTimeout is timeout of functions not in functionTimeouts. Overridden by -function-timeout flag.
+const defaultFunctionTimeout = 5 * time.Second
+
+// builtinFunctionTimeouts are timeouts of functions known to be slow.
+// Loading or saving presets with many inputs can take tens of seconds.
+var builtinFunctionTimeouts = map[string]time.Duration{
+	"OpenPreset":       60 * time.Second,
+	"SavePreset":       30 * time.Second,
+	"StartRecording":   15 * time.Second,
+	"StopRecording":    15 * time.Second,
+	"StartStreaming":   15 * time.Second,
+	"StartMultiCorder": 15 * time.Second,
+	"AddInput":         30 * time.Second,
+}
+
+// timeoutTable holds per-function timeouts. Timeouts from config override builtin ones.
+// Overrides are kept in memory only, so they are lost on restart unless exported by /api/config/export .
+type timeoutTable struct {
+	mu        sync.Mutex
+	fallback  time.Duration
+	overrides map[string]time.Duration
+}
+
+// get returns timeout of funcname.
+func (t *timeoutTable) get(funcname string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d, ok := t.overrides[funcname]; ok {
+		return d
+	}
+	if d, ok := builtinFunctionTimeouts[funcname]; ok {
+		return d
+	}
+	return t.fallback
+}
+
+// overridesMs returns timeouts from config in milliseconds.
+func (t *timeoutTable) overridesMs() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ms := make(map[string]int, len(t.overrides))
+	for k, v := range t.overrides {
+		ms[k] = int(v / time.Millisecond)
+	}
+	return ms
+}
+
+// replace replaces timeouts from config. ms is milliseconds keyed by function name.
+func (t *timeoutTable) replace(ms map[string]int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.overrides = make(map[string]time.Duration, len(ms))
+	for k, v := range ms {
+		t.overrides[k] = time.Duration(v) * time.Millisecond
+	}
+}
+
+// functionTimeouts are timeouts of functions sent to vMix.
+var functionTimeouts = &timeoutTable{fallback: defaultFunctionTimeout, overrides: map[string]time.Duration{}}
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// defaultTransitionDuration is used when duration is not specified. in milliseconds.
+const defaultTransitionDuration = 500
+
+// transitionEffects contains transition effects supported by vmix-go wrappers, keyed by vMix function name.
+// value reports whether the effect takes duration.
+var transitionEffects = map[string]bool{
+	"Fade":                 true,
+	"Zoom":                 true,
+	"Wipe":                 true,
+	"Slide":                true,
+	"Fly":                  true,
+	"CrossZoom":            true,
+	"FlyRotate":            true,
+	"Cube":                 true,
+	"CubeZoom":             true,
+	"VerticalWipe":         true,
+	"VerticalSlide":        true,
+	"Merge":                true,
+	"WipeReverse":          true,
+	"SlideReverse":         true,
+	"VerticalWipeReverse":  true,
+	"VerticalSlideReverse": true,
+	"Cut":                  false,
+	"CutDirect":            false,
+	"Stinger1":             false,
+	"Stinger2":             false,
+}
+
+// buttonEffectExcluded are transitionEffects which are functions rather than transition button effects,
+// so they cannot be set on a transition button.
+var buttonEffectExcluded = map[string]bool{"CutDirect": true}
+
+// buttonEffectNames returns sorted effect names valid for transition buttons.
+func buttonEffectNames() []string {
+	names := []string{}
+	for _, name := range transitionEffectNames() {
+		if !buttonEffectExcluded[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// transitionEffectNames returns sorted transition effect names.
+func transitionEffectNames() []string {
+	names := make([]string, 0, len(transitionEffects))
+	for name := range transitionEffects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TransitionRequest Request JSON for TransitionHandler
+type TransitionRequest struct {
+	Effect     string `json:"effect"`     // transition effect. e.g. "Fade" .
+	Input      string `json:"input"`      // input key, number or title. empty transitions Preview.
+	DurationMs uint   `json:"durationMs"` // duration in milliseconds. defaults to 500.
+}
+
+// TransitionHandler sends transition effect for [POST] /api/transition .
+func TransitionHandler(c *gin.Context) {
+	req := TransitionRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	takesDuration, ok := transitionEffects[req.Effect]
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   fmt.Sprintf("Unknown effect %q. valid effects: %s", req.Effect, strings.Join(transitionEffectNames(), ", ")),
+			"effects": transitionEffectNames(),
+		})
+		return
+	}
+	params := make(map[string]string)
+	if req.Input != "" {
+		input, ok := findInput(req.Input)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Input not found",
+			})
+			return
+		}
+		params["Input"] = input.Key
+	}
+	if takesDuration {
+		duration := req.DurationMs
+		if duration == 0 {
+			duration = defaultTransitionDuration
+		}
+		params["Duration"] = strconv.Itoa(int(duration))
+	}
+	vmix, _ := vmixSnapshot()
+	if err := sendFunction(vmix, req.Effect, params); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	transitionRunning.started(req.Effect, params["Duration"])
+	c.JSON(http.StatusOK, gin.H{
+		"effect": req.Effect,
+	})
+}
+
+// faderPosition is the last T-bar position set by vmix-utility.
+// vMix API XML does not expose T-bar position, so changes made in vMix itself are not reflected.
+var faderPosition = struct {
+	sync.Mutex
+	value int
+}{}
+
+// SetFader sets T-bar position. value is clamped to 0 to 255.
+func SetFader(v *vmixgo.Vmix, value int) error {
+	params := make(map[string]string)
+	params["Value"] = strconv.Itoa(int(clamp(float64(value), 0, 255)))
+	return sendFunction(v, "SetFader", params)
+}
+
+// FaderRequest Request JSON for SetFaderHandler
+type FaderRequest struct {
+	Value int `json:"value"` // T-bar position. 0 to 255.
+}
+
+// GetFaderHandler returns last T-bar position set by vmix-utility for [GET] /api/fader .
+func GetFaderHandler(c *gin.Context) {
+	faderPosition.Lock()
+	defer faderPosition.Unlock()
+	c.JSON(http.StatusOK, gin.H{
+		"value": faderPosition.value,
+	})
+}
+
+// SetFaderHandler sets T-bar position for [POST] /api/fader .
+func SetFaderHandler(c *gin.Context) {
+	req := FaderRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	value := int(clamp(float64(req.Value), 0, 255))
+	faderPosition.Lock()
+	defer faderPosition.Unlock()
+	vmix, _ := vmixSnapshot()
+	if err := SetFader(vmix, value); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	faderPosition.value = value
+	c.JSON(http.StatusOK, gin.H{
+		"value": value,
+	})
+}
+
+// transitionTimer tracks the last timed transition sent by vmix-utility.
+type transitionTimer struct {
+	sync.Mutex
+	effect   string
+	start    time.Time
+	duration time.Duration
+}
+
+// transitionRunning is the last timed transition sent by vmix-utility.
+var transitionRunning = &transitionTimer{}
+
+// started records effect sent now with duration in milliseconds. empty duration means the effect is instant.
+func (t *transitionTimer) started(effect string, duration string) {
+	ms, err := strconv.Atoi(duration)
+	if err != nil || ms <= 0 {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.effect, t.start, t.duration = effect, time.Now(), time.Duration(ms)*time.Millisecond
+}
+
+// progress returns effect and progress from 0 to 1 of the transition. ok is false if none is running.
+func (t *transitionTimer) progress(now time.Time) (effect string, progress float64, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+	elapsed := now.Sub(t.start)
+	if t.start.IsZero() || elapsed >= t.duration {
+		return "", 0, false
+	}
+	return t.effect, float64(elapsed) / float64(t.duration), true
+}
+
+// GetTransitionProgressHandler returns whether a transition is in progress for [GET] /api/transition/progress .
+// vMix API XML exposes neither transition progress nor T-bar position, so only transitions and T-bar moves
+// sent by vmix-utility are seen. Timed progress is estimated from the requested duration
+// and ignores latency to vMix. T-bar moved in vMix itself or by other controllers is not reflected.
+// "source" is "fader" if the T-bar is held mid-way, "timed" for a running transition, or "none".
+func GetTransitionProgressHandler(c *gin.Context) {
+	faderPosition.Lock()
+	fader := faderPosition.value
+	faderPosition.Unlock()
+	if fader > 0 && fader < 255 {
+		c.JSON(http.StatusOK, gin.H{
+			"in_progress": true,
+			"progress":    float64(fader) / 255,
+			"source":      "fader",
+		})
+		return
+	}
+	if effect, progress, ok := transitionRunning.progress(time.Now()); ok {
+		c.JSON(http.StatusOK, gin.H{
+			"in_progress": true,
+			"progress":    progress,
+			"effect":      effect,
+			"source":      "timed",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"in_progress": false,
+		"progress":    0,
+		"source":      "none",
+	})
+}
+
+// GetNextTransitionHandler returns transition fired by a plain take for [GET] /api/transition/next .
+// vMix API XML does not expose a selected transition separately, so it is transition button 1.
+func GetNextTransitionHandler(c *gin.Context) {
+	vmix, _ := vmixSnapshot()
+	for _, t := range vmix.Transitions.Transition {
+		if t.Number == 1 {
+			c.JSON(http.StatusOK, gin.H{
+				"number":   t.Number,
+				"effect":   t.Effect,
+				"duration": t.Duration,
+			})
+			return
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+		"error": "Transition not loaded",
+	})
+}
+
+// TransitionEffect is a transition effect supported by TransitionHandler.
+type TransitionEffect struct {
+	Name          string `json:"name"`           // effect name. e.g. "Fade" .
+	TakesDuration bool   `json:"takes_duration"` // whether durationMs is sent.
+}
+
+// GetTransitionEffectsHandler returns transition effects supported by [POST] /api/transition
+// for [GET] /api/transitions/effects .
+func GetTransitionEffectsHandler(c *gin.Context) {
+	effects := make([]TransitionEffect, 0, len(transitionEffects))
+	for _, name := range transitionEffectNames() {
+		effects = append(effects, TransitionEffect{Name: name, TakesDuration: transitionEffects[name]})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"effects":          effects,
+		"default_duration": defaultTransitionDuration,
+	})
+}
+
+// Stinger transitions input to Output with stinger n. n is 1 or 2.
+func Stinger(v *vmixgo.Vmix, n int, input string) error {
+	if n != 1 && n != 2 {
+		return fmt.Errorf("stinger must be 1 or 2")
+	}
+	params := make(map[string]string)
+	params["Input"] = input
+	return sendFunction(v, "Stinger"+strconv.Itoa(n), params)
+}
+
+// StingerRequest Request JSON for StingerHandler
+type StingerRequest struct {
+	Input string `json:"input"` // input key, number or title.
+}
+
+// StingerHandler transitions input with stinger for [POST] /api/transition/stinger/:n .
+func StingerHandler(c *gin.Context) {
+	n, err := strconv.Atoi(c.Param("n"))
+	if err != nil || (n != 1 && n != 2) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": "stinger must be 1 or 2",
+		})
+		return
+	}
+	req := StingerRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	input, ok := findInput(req.Input)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Input not found",
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	if err := Stinger(vmix, n, input.Key); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"stinger": n,
+		"input":   input.Key,
+	})
+}
+
+// transitionButtons is the number of vMix transition buttons.
+const transitionButtons = 4
+
+// SetTransitionEffect sets effect of transition button n. n is 1 to 4.
+func SetTransitionEffect(v *vmixgo.Vmix, n int, effect string) error {
+	if n < 1 || n > transitionButtons {
+		return fmt.Errorf("transition button must be 1 to %d", transitionButtons)
+	}
+	params := make(map[string]string)
+	params["Value"] = effect
+	return sendFunction(v, "SetTransitionEffect"+strconv.Itoa(n), params)
+}
+
+// SetTransitionDuration sets duration of transition button n in milliseconds. n is 1 to 4.
+func SetTransitionDuration(v *vmixgo.Vmix, n int, duration uint) error {
+	if n < 1 || n > transitionButtons {
+		return fmt.Errorf("transition button must be 1 to %d", transitionButtons)
+	}
+	params := make(map[string]string)
+	params["Value"] = strconv.Itoa(int(duration))
+	return sendFunction(v, "SetTransitionDuration"+strconv.Itoa(n), params)
+}
+
+// TransitionButton is effect and duration of a transition button.
+type TransitionButton struct {
+	Effect     string `json:"effect"`     // transition effect. e.g. "Fade" .
+	DurationMs uint   `json:"durationMs"` // duration in milliseconds. defaults to 500.
+}
+
+// TransitionTemplateRequest Request JSON for TransitionTemplateHandler
+type TransitionTemplateRequest struct {
+	Buttons []TransitionButton `json:"buttons"` // transition buttons 1 to 4 in order.
+}
+
+// Validate form and fill default durations.
+func (r *TransitionTemplateRequest) Validate() error {
+	if len(r.Buttons) != transitionButtons {
+		return fmt.Errorf("buttons must have %d entries", transitionButtons)
+	}
+	for i := range r.Buttons {
+		b := &r.Buttons[i]
+		if _, ok := transitionEffects[b.Effect]; !ok || buttonEffectExcluded[b.Effect] {
+			return fmt.Errorf("buttons[%d] : Invalid button effect %q. valid effects: %s", i, b.Effect, strings.Join(buttonEffectNames(), ", "))
+		}
+		if b.DurationMs == 0 {
+			b.DurationMs = defaultTransitionDuration
+		}
+	}
+	return nil
+}
+
+// transitionButtonsResponse returns transition buttons of v.
+func transitionButtonsResponse(v *vmixgo.Vmix) []gin.H {
+	buttons := make([]gin.H, 0, len(v.Transitions.Transition))
+	for _, t := range v.Transitions.Transition {
+		buttons = append(buttons, gin.H{
+			"number":   t.Number,
+			"effect":   t.Effect,
+			"duration": t.Duration,
+		})
+	}
+	return buttons
+}
+
+// TransitionTemplateHandler sets effect and duration of all transition buttons for [POST] /api/transitions/template
+// and returns the resulting buttons read back from vMix.
+// Every button is validated before any function is sent.
+func TransitionTemplateHandler(c *gin.Context) {
+	req := TransitionTemplateRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": bindError(err).Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ := vmixSnapshot()
+	for i, b := range req.Buttons {
+		err := SetTransitionEffect(vmix, i+1, b.Effect)
+		if err == nil {
+			err = SetTransitionDuration(vmix, i+1, b.DurationMs)
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("button %d : %v", i+1, err),
+			})
+			return
+		}
+	}
+	if err := refreshvMix(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	vmix, _ = vmixSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"buttons": transitionButtonsResponse(vmix),
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransitionHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/transition", `{"effect":"Merge","input":"2","durationMs":1000}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "Merge" || calls[0].Query.Get("Input") != vmix.Inputs.Input[1].Key || calls[0].Query.Get("Duration") != "1000" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/transition", `{"effect":"Explode"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFaderHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/fader", `{"value":300}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 1 || calls[0].Function != "SetFader" || calls[0].Query.Get("Value") != "255" {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	w = doRequest(r, http.MethodGet, "/api/fader", "")
+	if w.Body.String() != `{"value":255}` {
+		t.Errorf("body = %s, want {\"value\":255}", w.Body.String())
+	}
+}
+
+func TestGetNextTransitionHandler(t *testing.T) {
+	_, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/transition/next", "")
+	if w.Code != http.StatusOK || w.Body.String() != `{"duration":500,"effect":"Fade","number":1}` {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetTransitionEffectsHandler(t *testing.T) {
+	_, r := setupTest(t)
+	w := doRequest(r, http.MethodGet, "/api/transitions/effects", "")
+	for _, want := range []string{`{"name":"Fade","takes_duration":true}`, `{"name":"Stinger1","takes_duration":false}`} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("effects do not contain %s: %s", want, w.Body.String())
+		}
+	}
+}
+
+func TestStingerHandler(t *testing.T) {
+	s, r := setupTest(t)
+	w := doRequest(r, http.MethodPost, "/api/transition/stinger/2", `{"input":"opener.mp4"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if calls := s.Calls(); len(calls) != 1 || calls[0].Function != "Stinger2" || calls[0].Query.Get("Input") != vmix.Inputs.Input[1].Key {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+	for path, want := range map[string]int{
+		"/api/transition/stinger/3": http.StatusBadRequest,
+		"/api/transition/stinger/x": http.StatusBadRequest,
+	} {
+		if w := doRequest(r, http.MethodPost, path, `{"input":"1"}`); w.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, want)
+		}
+	}
+	if w := doRequest(r, http.MethodPost, "/api/transition/stinger/1", `{"input":"missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestTransitionTemplateHandler(t *testing.T) {
+	s, r := setupTest(t)
+	body := `{"buttons":[{"effect":"Fade","durationMs":300},{"effect":"Cut"},{"effect":"Wipe","durationMs":800},{"effect":"Stinger1"}]}`
+	w := doRequest(r, http.MethodPost, "/api/transitions/template", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	calls := s.Calls()
+	if len(calls) != 8 {
+		t.Fatalf("len(Calls) = %d, want 8", len(calls))
+	}
+	if calls[0].Function != "SetTransitionEffect1" || calls[0].Query.Get("Value") != "Fade" {
+		t.Errorf("unexpected call: %v", calls[0].Query)
+	}
+	if calls[1].Function != "SetTransitionDuration1" || calls[1].Query.Get("Value") != "300" {
+		t.Errorf("unexpected call: %v", calls[1].Query)
+	}
+	if calls[3].Function != "SetTransitionDuration2" || calls[3].Query.Get("Value") != "500" {
+		t.Errorf("default duration not sent: %v", calls[3].Query)
+	}
+	if !strings.Contains(w.Body.String(), `{"duration":1000,"effect":"CubeZoom","number":4}`) {
+		t.Errorf("resulting buttons not returned: %s", w.Body.String())
+	}
+
+	s.Reset()
+	for _, body := range []string{
+		`{"buttons":[{"effect":"Fade"}]}`,
+		`{"buttons":[{"effect":"Fade"},{"effect":"Fade"},{"effect":"Fade"},{"effect":"Spin"}]}`,
+		`{"buttons":[{"effect":"Fade"},{"effect":"CutDirect"},{"effect":"Fade"},{"effect":"Fade"}]}`,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/transitions/template", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if len(s.Calls()) != 0 {
+		t.Error("functions sent despite invalid template")
+	}
+}
+
+func TestGetTransitionProgressHandler(t *testing.T) {
+	_, r := setupTest(t)
+	transitionRunning = &transitionTimer{}
+	defer func() {
+		transitionRunning = &transitionTimer{}
+		faderPosition.value = 0
+	}()
+
+	w := doRequest(r, http.MethodGet, "/api/transition/progress", "")
+	if w.Body.String() != `{"in_progress":false,"progress":0,"source":"none"}` {
+		t.Errorf("unexpected idle progress: %s", w.Body.String())
+	}
+
+	doRequest(r, http.MethodPost, "/api/transition", `{"effect":"Fade","durationMs":60000}`)
+	w = doRequest(r, http.MethodGet, "/api/transition/progress", "")
+	if !strings.Contains(w.Body.String(), `"effect":"Fade","in_progress":true`) || !strings.Contains(w.Body.String(), `"source":"timed"`) {
+		t.Errorf("unexpected timed progress: %s", w.Body.String())
+	}
+	if _, progress, ok := transitionRunning.progress(time.Now().Add(30 * time.Second)); !ok || progress < 0.49 || progress > 0.51 {
+		t.Errorf("progress at half = %v, %v", progress, ok)
+	}
+	if _, _, ok := transitionRunning.progress(time.Now().Add(time.Minute)); ok {
+		t.Error("transition still running after its duration")
+	}
+
+	doRequest(r, http.MethodPost, "/api/fader", `{"value":51}`)
+	w = doRequest(r, http.MethodGet, "/api/transition/progress", "")
+	if w.Body.String() != `{"in_progress":true,"progress":0.2,"source":"fader"}` {
+		t.Errorf("unexpected fader progress: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FunctionUsage is how often a function was sent to vMix.
+type FunctionUsage struct {
+	Count    int       `json:"count"`     // successful sends.
+	LastUsed time.Time `json:"last_used"` // last successful send.
+}
+
+// usageStats counts functions sent to vMix to sort shortcuts by usage.
+// Stats are kept in memory only, so they are lost on restart unless exported by /api/config/export .
+type usageStats struct {
+	mu    sync.Mutex
+	usage map[string]FunctionUsage
+}
+
+// record counts funcname as used now.
+func (s *usageStats) record(funcname string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usage[funcname]
+	u.Count++
+	u.LastUsed = time.Now()
+	s.usage[funcname] = u
+}
+
+// get returns copy of usage stats.
+func (s *usageStats) get() map[string]FunctionUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage := make(map[string]FunctionUsage, len(s.usage))
+	for k, v := range s.usage {
+		usage[k] = v
+	}
+	return usage
+}
+
+// replace replaces all usage stats. nil clears them.
+func (s *usageStats) replace(usage map[string]FunctionUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = make(map[string]FunctionUsage, len(usage))
+	for k, v := range usage {
+		s.usage[k] = v
+	}
+}
+
+// sortFunctions returns copy of functions sorted by order.
+// "usage" puts most used and then most recently used functions first, "alphabetical" sorts by name.
+// Functions never used keep their original order after used ones.
+func sortFunctions(functions []vMixFunction, usage map[string]FunctionUsage, order string) []vMixFunction {
+	sorted := make([]vMixFunction, len(functions))
+	copy(sorted, functions)
+	if order == "alphabetical" {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Name < sorted[j].Name
+		})
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := usage[sorted[i].Name], usage[sorted[j].Name]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+	return sorted
+}
+
+// functionUsage is usage stats of functions sent by this server.
+var functionUsage = &usageStats{usage: map[string]FunctionUsage{}}
+
+// ResetUsageHandler clears function usage stats for [DELETE] /api/functions/usage .
+func ResetUsageHandler(c *gin.Context) {
+	functionUsage.replace(nil)
+	c.Status(http.StatusNoContent)
+}
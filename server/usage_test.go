@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSortFunctions(t *testing.T) {
+	functions := []vMixFunction{{Name: "Fade"}, {Name: "Cut"}, {Name: "Merge"}, {Name: "AudioOn"}}
+	now := time.Now()
+	usage := map[string]FunctionUsage{
+		"Merge": {Count: 3, LastUsed: now.Add(-time.Hour)},
+		"Cut":   {Count: 3, LastUsed: now},
+		"Fade":  {Count: 1, LastUsed: now},
+	}
+	names := func(functions []vMixFunction) []string {
+		n := []string{}
+		for _, f := range functions {
+			n = append(n, f.Name)
+		}
+		return n
+	}
+	for order, want := range map[string][]string{
+		"usage":        {"Cut", "Merge", "Fade", "AudioOn"},
+		"alphabetical": {"AudioOn", "Cut", "Fade", "Merge"},
+	} {
+		got := names(sortFunctions(functions, usage, order))
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: got %v, want %v", order, got, want)
+				break
+			}
+		}
+	}
+	if functions[0].Name != "Fade" {
+		t.Error("sortFunctions modified original slice")
+	}
+}
+
+func TestFunctionUsage(t *testing.T) {
+	_, r := setupTest(t)
+	functionUsage.replace(nil)
+	defer functionUsage.replace(nil)
+
+	if w := doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut"}`); w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if u := functionUsage.get()["Cut"]; u.Count != 1 {
+		t.Errorf("Cut count = %d, want 1", u.Count)
+	}
+	if w := doRequest(r, http.MethodDelete, "/api/functions/usage", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if len(functionUsage.get()) != 0 {
+		t.Error("usage not cleared")
+	}
+	if w := doRequest(r, http.MethodGet, "/api/functions?sort=random", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// clamp limits value between min and max.
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// formatFloat formats float value for vMix function Value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// bindError describes JSON binding error for clients.
+func bindError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("Request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("Malformed JSON : unexpected end of body")
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("Malformed JSON at offset %d : %v", syntaxErr.Offset, syntaxErr)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("Field %q must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// verify settings
+const (
+	defaultVerifyTimeout = 2000  // ms
+	maxVerifyTimeout     = 10000 // ms
+)
+
+// verifyInterval is interval to refresh vMix XML while verifying.
+var verifyInterval = 100 * time.Millisecond
+
+// verifyFields are vMix state fields which can be verified after sending function.
+var verifyFields = map[string]func(v *vmixgo.Vmix) string{
+	"recording":     func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.Recording) },
+	"streaming":     func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.Streaming) },
+	"external":      func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.External) },
+	"fullscreen":    func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.FullScreen) },
+	"multicorder":   func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.MultiCorder) },
+	"playlist":      func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.PlayList) },
+	"fade_to_black": func(v *vmixgo.Vmix) string { return strconv.FormatBool(v.IsFadeToBlack) },
+	"active":        func(v *vmixgo.Vmix) string { return strconv.FormatUint(uint64(v.Active), 10) },
+	"preview":       func(v *vmixgo.Vmix) string { return strconv.FormatUint(uint64(v.Preview), 10) },
+}
+
+// defaultVerifications are field and value verified by default for each function.
+var defaultVerifications = map[string][2]string{
+	"StartRecording":   {"recording", "true"},
+	"StopRecording":    {"recording", "false"},
+	"StartStreaming":   {"streaming", "true"},
+	"StopStreaming":    {"streaming", "false"},
+	"StartExternal":    {"external", "true"},
+	"StopExternal":     {"external", "false"},
+	"StartMultiCorder": {"multicorder", "true"},
+	"StopMultiCorder":  {"multicorder", "false"},
+	"StartPlayList":    {"playlist", "true"},
+	"StopPlayList":     {"playlist", "false"},
+}
+
+// VerifyRequest is a state to verify after sending function.
+type VerifyRequest struct {
+	Field     string `json:"field"`      // field in verifyFields. empty uses default of the function.
+	Value     string `json:"value"`      // expected value. e.g. "true" or input number for active/preview.
+	TimeoutMs int    `json:"timeout_ms"` // how long to wait. default 2000, max 10000.
+}
+
+// resolve fills default field and value of funcname, and validates them.
+func (r *VerifyRequest) resolve(funcname string) error {
+	if r.Field == "" {
+		d, ok := defaultVerifications[funcname]
+		if !ok {
+			return fmt.Errorf("verify field required. %s has no default", funcname)
+		}
+		r.Field, r.Value = d[0], d[1]
+	}
+	if _, ok := verifyFields[r.Field]; !ok {
+		fields := make([]string, 0, len(verifyFields))
+		for f := range verifyFields {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		return fmt.Errorf("Unknown verify field %q. must be one of %v", r.Field, fields)
+	}
+	if r.Value == "" {
+		return fmt.Errorf("verify value required")
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = defaultVerifyTimeout
+	}
+	if r.TimeoutMs < 0 || r.TimeoutMs > maxVerifyTimeout {
+		return fmt.Errorf("verify timeout_ms must be between 1 and %d", maxVerifyTimeout)
+	}
+	return nil
+}
+
+// verifyState refreshes vMix until field becomes expected value or timeout.
+// It returns last seen value.
+func verifyState(r VerifyRequest) (ok bool, actual string) {
+	get := verifyFields[r.Field]
+	deadline := time.Now().Add(time.Duration(r.TimeoutMs) * time.Millisecond)
+	for {
+		if err := refreshvMix(); err == nil {
+			vmix, _ := vmixSnapshot()
+			actual = get(vmix)
+			if actual == r.Value {
+				return true, actual
+			}
+		}
+		if time.Now().After(deadline) {
+			return false, actual
+		}
+		time.Sleep(verifyInterval)
+	}
+}
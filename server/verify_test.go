@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestDoFunctionHandlerVerify(t *testing.T) {
+	s, r := setupTest(t)
+	verifyInterval = 10 * time.Millisecond
+
+	go func() {
+		for i := 0; i < 100 && len(s.Calls()) == 0; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+		s.SetXML(strings.Replace(vmixtest.DefaultXML, "<recording>False</recording>", "<recording>True</recording>", 1))
+	}()
+	w := doRequest(r, http.MethodPost, "/api/function", `{"function":"StartRecording","verify":{}}`)
+	if !strings.Contains(w.Body.String(), `"verified":true`) {
+		t.Errorf("recording not verified: %s", w.Body.String())
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/function", `{"function":"Cut","verify":{"field":"active","value":"2","timeout_ms":50}}`)
+	if !strings.Contains(w.Body.String(), `"verified":false`) || !strings.Contains(w.Body.String(), `"actual":"1"`) {
+		t.Errorf("unexpected result: %s", w.Body.String())
+	}
+
+	for _, body := range []string{
+		`{"function":"Cut","verify":{}}`,
+		`{"function":"Cut","verify":{"field":"unknown","value":"1"}}`,
+		`{"function":"Cut","verify":{"field":"active","value":"1","timeout_ms":60000}}`,
+	} {
+		if w := doRequest(r, http.MethodPost, "/api/function", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
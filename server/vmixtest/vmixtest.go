@@ -0,0 +1,145 @@
+// Package vmixtest provides a mock vMix Web API server for hermetic tests.
+package vmixtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+)
+
+// DefaultXML is a small but realistic /api response from vMix.
+const DefaultXML = `<vmix>
+<version>24.0.0.72</version>
+<edition>4K</edition>
+<preset>C:\Users\vmix\Documents\show.vmix</preset>
+<inputs>
+<input key="0b1a9c6e-1111-4c7a-9a53-6f2b4d1e0001" number="1" type="Capture" title="CAM 1" shortTitle="CAM 1" state="Running" position="0" duration="0" loop="False" muted="False" volume="100" balance="0" solo="False" audiobusses="M" meterF1="0.1" meterF2="0.1" gainDb="0">CAM 1</input>
+<input key="0b1a9c6e-2222-4c7a-9a53-6f2b4d1e0002" number="2" type="Video" title="opener.mp4" shortTitle="opener" state="Paused" position="0" duration="15000" loop="False" muted="True" volume="80" balance="0" solo="False" audiobusses="M,A" meterF1="0" meterF2="0" gainDb="0">opener.mp4</input>
+<input key="0b1a9c6e-3333-4c7a-9a53-6f2b4d1e0003" number="3" type="GT" title="Lower Third.gtzip" shortTitle="Lower Third" state="Paused" position="0" duration="0" loop="False">Lower Third.gtzip<text index="0" name="Headline.Text">Hello</text></input>
+</inputs>
+<overlays>
+<overlay number="1">3</overlay>
+<overlay number="2" />
+<overlay number="3" />
+<overlay number="4" />
+<overlay number="5" />
+<overlay number="6" />
+</overlays>
+<preview>2</preview>
+<active>1</active>
+<fadeToBlack>False</fadeToBlack>
+<transitions>
+<transition number="1" effect="Fade" duration="500" />
+<transition number="2" effect="Merge" duration="1000" />
+<transition number="3" effect="Wipe" duration="1000" />
+<transition number="4" effect="CubeZoom" duration="1000" />
+</transitions>
+<recording>False</recording>
+<external>False</external>
+<streaming>False</streaming>
+<playList>False</playList>
+<multiCorder>False</multiCorder>
+<fullscreen>False</fullscreen>
+<audio>
+<master volume="100" muted="False" meterF1="0.02" meterF2="0.02" headphonesVolume="74.36" />
+<busA volume="100" muted="False" meterF1="0.3" meterF2="0.25" solo="False" sendToMaster="False" />
+</audio>
+<mix number="2">
+<active>3</active>
+<preview>1</preview>
+</mix>
+</vmix>`
+
+// Call is a function call received by Server.
+type Call struct {
+	Function string     // Function name. e.g. "Fade" .
+	Method   string     // HTTP method. GET, or POST for form-encoded functions.
+	Query    url.Values // Full query or form, including Function.
+}
+
+// Server is a mock vMix Web API. It serves XML on /api and records every Function call, sent by query or POST form.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	xml       string
+	xmlStatus int // XML response status
+	calls     []Call
+	status    int    // function response status
+	response  string // function response body
+}
+
+// NewServer starts a mock vMix serving xml on /api. Close it when done.
+func NewServer(xml string) *Server {
+	s := &Server{
+		xml:       xml,
+		xmlStatus: http.StatusOK,
+		status:    http.StatusOK,
+		response:  "Function completed successfully.",
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api" && r.URL.Path != "/api/" {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.Form
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fn := q.Get("Function"); fn != "" {
+		s.calls = append(s.calls, Call{Function: fn, Method: r.Method, Query: q})
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.response))
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(s.xmlStatus)
+	w.Write([]byte(s.xml))
+}
+
+// SetXML replaces the XML served on /api.
+func (s *Server) SetXML(xml string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.xml = xml
+}
+
+// SetXMLResponse replaces status and body served on /api. e.g. 500 with an error page.
+func (s *Server) SetXMLResponse(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.xmlStatus = status
+	s.xml = body
+}
+
+// SetFunctionResponse replaces the response to function calls. Default is 200 "Function completed successfully." .
+func (s *Server) SetFunctionResponse(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.response = body
+}
+
+// Calls returns a copy of the function calls received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, len(s.calls))
+	copy(calls, s.calls)
+	return calls
+}
+
+// Reset clears recorded function calls.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
@@ -0,0 +1,67 @@
+package vmixtest
+
+import (
+	"testing"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+func TestRefresh(t *testing.T) {
+	s := NewServer(DefaultXML)
+	defer s.Close()
+
+	vmix, err := vmixgo.NewVmix(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vmix.Version != "24.0.0.72" {
+		t.Errorf("Version = %q, want %q", vmix.Version, "24.0.0.72")
+	}
+	if len(vmix.Inputs.Input) != 3 {
+		t.Fatalf("len(Inputs) = %d, want 3", len(vmix.Inputs.Input))
+	}
+
+	s.SetXML(`<vmix><version>25.0.0.1</version></vmix>`)
+	vmix, err = vmix.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vmix.Version != "25.0.0.1" {
+		t.Errorf("Version after Refresh = %q, want %q", vmix.Version, "25.0.0.1")
+	}
+	if len(vmix.Inputs.Input) != 0 {
+		t.Errorf("len(Inputs) after Refresh = %d, want 0", len(vmix.Inputs.Input))
+	}
+}
+
+func TestSendFunction(t *testing.T) {
+	s := NewServer(DefaultXML)
+	defer s.Close()
+
+	vmix, err := vmixgo.NewVmix(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vmix.Fade(1, 500); err != nil {
+		t.Fatal(err)
+	}
+	if err := vmix.SendFunction("SetText", map[string]string{"Input": "3", "SelectedName": "Headline.Text", "Value": "a b&c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := s.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("len(Calls) = %d, want 2", len(calls))
+	}
+	if calls[0].Function != "Fade" || calls[0].Query.Get("Input") != "1" || calls[0].Query.Get("Duration") != "500" {
+		t.Errorf("unexpected Fade call: %v", calls[0].Query)
+	}
+	if calls[1].Function != "SetText" || calls[1].Query.Get("Value") != "a b&c" {
+		t.Errorf("unexpected SetText call: %v", calls[1].Query)
+	}
+
+	s.Reset()
+	if len(s.Calls()) != 0 {
+		t.Error("Reset did not clear calls")
+	}
+}
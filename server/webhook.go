@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	vmixgo "github.com/FlowingSPDG/vmix-go"
+)
+
+// Webhook events
+const (
+	EventInputLive        = "input_live"        // input went to Program.
+	EventRecordingStarted = "recording_started" // recording started.
+	EventHostDisconnected = "host_disconnected" // vMix stopped responding.
+	EventTitleChanged     = "title_changed"     // title text field value changed.
+	EventPlaybackEnding   = "playback_ending"   // playing video reached -playback-end-threshold before its end.
+	EventTallyChanged     = "tally_changed"     // tally of any input changed. payload has the whole table.
+)
+
+var webhookEvents = map[string]bool{
+	EventInputLive:        true,
+	EventRecordingStarted: true,
+	EventHostDisconnected: true,
+	EventTitleChanged:     true,
+	EventPlaybackEnding:   true,
+	EventTallyChanged:     true,
+}
+
+// webhook delivery settings
+var (
+	webhookRetries = 3               // retries after first failed delivery
+	webhookBackoff = 1 * time.Second // doubled on each retry
+	webhookClient  = &http.Client{Timeout: 5 * time.Second}
+)
+
+// playbackEndThreshold is remaining time of playing video which fires playback_ending.
+var playbackEndThreshold = 2 * time.Second
+
+// Webhook is an URL which receives POST request on events.
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`              // destination URL.
+	Events []string `json:"events"`           // events to send. e.g. ["input_live"] .
+	Input  string   `json:"input,omitempty"`  // optional input key filter for input events.
+	Secret string   `json:"secret,omitempty"` // optional HMAC-SHA256 key. signature is sent in X-Signature header.
+}
+
+// WebhookResponse is Webhook returned by the API. Secret is never returned, only whether it is set.
+// The raw secret is only included in /api/config/export .
+type WebhookResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Input     string   `json:"input,omitempty"`
+	HasSecret bool     `json:"has_secret"` // whether Secret is set.
+}
+
+// redacted returns w without Secret.
+func (w Webhook) redacted() WebhookResponse {
+	return WebhookResponse{ID: w.ID, URL: w.URL, Events: w.Events, Input: w.Input, HasSecret: w.Secret != ""}
+}
+
+// Validate form
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Invalid URL")
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("Events empty")
+	}
+	for _, e := range w.Events {
+		if !webhookEvents[e] {
+			return fmt.Errorf("Unknown event %q", e)
+		}
+	}
+	return nil
+}
+
+func (w *Webhook) subscribes(e WebhookEvent) bool {
+	for _, name := range w.Events {
+		if name == e.Event {
+			return w.Input == "" || e.Input == "" || w.Input == e.Input
+		}
+	}
+	return false
+}
+
+// WebhookEvent is a payload sent to webhooks.
+type WebhookEvent struct {
+	Event       string    `json:"event"`                  // event name.
+	Input       string    `json:"input,omitempty"`        // input key for input events.
+	Title       string    `json:"title,omitempty"`        // input title for input events.
+	Field       string    `json:"field,omitempty"`        // title field name for title_changed.
+	Value       string    `json:"value,omitempty"`        // new title field value for title_changed.
+	RemainingMs int       `json:"remaining_ms,omitempty"` // remaining playback time for playback_ending.
+	Tally       []Tally   `json:"tally,omitempty"`        // tally of every input for tally_changed.
+	Time        time.Time `json:"time"`
+}
+
+// webhookStore holds registered webhooks in memory only. They are lost on restart unless exported by /api/config/export .
+type webhookStore struct {
+	mu     sync.Mutex
+	hooks  map[string]Webhook
+	nextID int
+}
+
+var webhooks = &webhookStore{hooks: map[string]Webhook{}}
+
+func (s *webhookStore) list() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hooks := make([]Webhook, 0, len(s.hooks))
+	for _, h := range s.hooks {
+		hooks = append(hooks, h)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].ID < hooks[j].ID })
+	return hooks
+}
+
+func (s *webhookStore) add(h Webhook) Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	h.ID = strconv.Itoa(s.nextID)
+	s.hooks[h.ID] = h
+	return h
+}
+
+// update replaces webhook h.ID by h. Secret is kept if h has none, as clients cannot read it back.
+func (s *webhookStore) update(h Webhook) (Webhook, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.hooks[h.ID]
+	if !ok {
+		return Webhook{}, false
+	}
+	if h.Secret == "" {
+		h.Secret = old.Secret
+	}
+	s.hooks[h.ID] = h
+	return h, true
+}
+
+func (s *webhookStore) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.hooks[id]; !ok {
+		return false
+	}
+	delete(s.hooks, id)
+	return true
+}
+
+// replace replaces all webhooks by hooks. IDs are kept, or assigned when empty.
+func (s *webhookStore) replace(hooks []Webhook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = map[string]Webhook{}
+	for _, h := range hooks {
+		if h.ID == "" {
+			s.nextID++
+			h.ID = strconv.Itoa(s.nextID)
+		}
+		if id, err := strconv.Atoi(h.ID); err == nil && id > s.nextID {
+			s.nextID = id
+		}
+		s.hooks[h.ID] = h
+	}
+}
+
+// dispatch sends event to every subscribing webhook in background.
+func (s *webhookStore) dispatch(e WebhookEvent) {
+	for _, h := range s.list() {
+		if h.subscribes(e) {
+			go deliverWebhook(h, e)
+		}
+	}
+}
+
+// deliverWebhook POSTs event to h, retrying with exponential backoff.
+func deliverWebhook(h Webhook, e WebhookEvent) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Failed to marshal webhook event %v : %v\n", e, err)
+		return
+	}
+	backoff := webhookBackoff
+	for i := 0; ; i++ {
+		err = postWebhook(h, body)
+		if err == nil {
+			return
+		}
+		if i >= webhookRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	log.Printf("Failed to deliver webhook %s event %s : %v\n", h.URL, e.Event, err)
+}
+
+func postWebhook(h Webhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(h.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookEventsBetween returns events caused by change from prev to next.
+func webhookEventsBetween(prev, next *vmixgo.Vmix, prevExtra, nextExtra *vMixExtra) []WebhookEvent {
+	events := []WebhookEvent{}
+	now := time.Now()
+	if prev.Active != 0 && prev.Active != next.Active {
+		for _, input := range next.Inputs.Input {
+			if input.Number == next.Active {
+				events = append(events, WebhookEvent{Event: EventInputLive, Input: input.Key, Title: input.Title, Time: now})
+			}
+		}
+	}
+	if prev.Version != "" && !prev.Recording && next.Recording {
+		events = append(events, WebhookEvent{Event: EventRecordingStarted, Time: now})
+	}
+	// Fired once when remaining time crosses the threshold. Looping inputs never end, so they are ignored.
+	threshold := int(playbackEndThreshold / time.Millisecond)
+	for _, input := range next.Inputs.Input {
+		if input.Loop || input.Duration <= 0 || input.State != "Running" {
+			continue
+		}
+		remaining := input.Duration - input.AttrPosition
+		if remaining > threshold || remaining < 0 {
+			continue
+		}
+		for _, p := range prev.Inputs.Input {
+			if p.Key == input.Key && p.Duration-p.AttrPosition > threshold {
+				events = append(events, WebhookEvent{Event: EventPlaybackEnding, Input: input.Key, Title: input.Title, RemainingMs: remaining, Time: now})
+			}
+		}
+	}
+	// Fields of inputs added or removed are not reported.
+	for _, input := range next.Inputs.Input {
+		before, ok := prevExtra.input(input.Key)
+		if !ok {
+			continue
+		}
+		after, _ := nextExtra.input(input.Key)
+		for _, t := range after.Text {
+			if old, ok := before.text(t.Name); ok && old != t.Value {
+				events = append(events, WebhookEvent{Event: EventTitleChanged, Input: input.Key, Title: input.Title, Field: t.Name, Value: t.Value, Time: now})
+			}
+		}
+	}
+	if prev.Version != "" && tallyChanged(prev, next, prevExtra, nextExtra) {
+		events = append(events, WebhookEvent{Event: EventTallyChanged, Tally: tallyTable(next, nextExtra), Time: now})
+	}
+	return events
+}
+
+// GetWebhooksHandler returns webhooks for [GET] /api/webhooks . Secrets are redacted.
+func GetWebhooksHandler(c *gin.Context) {
+	hooks := []WebhookResponse{}
+	for _, h := range webhooks.list() {
+		hooks = append(hooks, h.redacted())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": hooks,
+	})
+}
+
+// AddWebhookHandler registers webhook for [POST] /api/webhooks .
+func AddWebhookHandler(c *gin.Context) {
+	h := Webhook{}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := h.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, webhooks.add(h).redacted())
+}
+
+// UpdateWebhookHandler replaces webhook for [PUT] /api/webhooks/:id .
+// Omitted secret keeps the current one. Delete and add the webhook again to remove its secret.
+func UpdateWebhookHandler(c *gin.Context) {
+	h := Webhook{}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err := h.Validate(); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	h.ID = c.Param("id")
+	h, ok := webhooks.update(h)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, h.redacted())
+}
+
+// DeleteWebhookHandler removes webhook for [DELETE] /api/webhooks/:id .
+func DeleteWebhookHandler(c *gin.Context) {
+	if !webhooks.remove(c.Param("id")) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
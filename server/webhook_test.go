@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FlowingSPDG/vmix-utility/server/vmixtest"
+)
+
+func TestWebhookInputLive(t *testing.T) {
+	s, r := setupTest(t)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		received <- r
+		bodies <- b
+	}))
+	defer receiver.Close()
+
+	w := doRequest(r, http.MethodPost, "/api/webhooks", `{"url":"`+receiver.URL+`","events":["input_live"],"secret":"s3cret"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	hook := Webhook{}
+	json.Unmarshal(w.Body.Bytes(), &hook)
+	defer webhooks.remove(hook.ID)
+
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, "<active>1</active>", "<active>2</active>", 1))
+	if err := refreshvMix(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case req := <-received:
+		body := <-bodies
+		e := WebhookEvent{}
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Event != EventInputLive || e.Input != vmix.Inputs.Input[1].Key {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		if got, want := req.Header.Get("X-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+			t.Errorf("X-Signature = %s, want %s", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	w = doRequest(r, http.MethodPost, "/api/webhooks", `{"url":"ftp://example.com","events":["input_live"]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestWebhookEventsBetweenTitleChanged(t *testing.T) {
+	s, _ := setupTest(t)
+	prev, prevExtra := vmix, vmixExtra
+
+	s.SetXML(strings.Replace(vmixtest.DefaultXML, ">Hello<", ">Goodbye<", 1))
+	if err := fetchvMix(); err != nil {
+		t.Fatal(err)
+	}
+	events := webhookEventsBetween(prev, vmix, prevExtra, vmixExtra)
+	if len(events) != 1 {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if e := events[0]; e.Event != EventTitleChanged || e.Input != vmix.Inputs.Input[2].Key || e.Field != "Headline.Text" || e.Value != "Goodbye" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if events := webhookEventsBetween(vmix, vmix, vmixExtra, vmixExtra); len(events) != 0 {
+		t.Errorf("unchanged fields emitted events: %+v", events)
+	}
+}
+
+func TestWebhookEventsBetweenPlaybackEnding(t *testing.T) {
+	s, _ := setupTest(t)
+	playing := strings.Replace(vmixtest.DefaultXML, `state="Paused" position="0" duration="15000" loop="False"`, `state="Running" position="%d" duration="15000" loop="%s"`, 1)
+
+	refresh := func(position int, loop string) {
+		s.SetXML(fmt.Sprintf(playing, position, loop))
+		if err := fetchvMix(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refresh(10000, "False")
+	for _, tt := range []struct {
+		position int
+		loop     string
+		want     int
+	}{
+		{12000, "False", 0}, // 3s remaining
+		{13500, "False", 1}, // crossed 2s
+		{14000, "False", 0}, // already fired
+		{10000, "True", 0},
+		{14000, "True", 0}, // looping
+	} {
+		prev, prevExtra := vmix, vmixExtra
+		refresh(tt.position, tt.loop)
+		events := webhookEventsBetween(prev, vmix, prevExtra, vmixExtra)
+		if len(events) != tt.want {
+			t.Errorf("position %d loop %s: unexpected events: %+v", tt.position, tt.loop, events)
+		}
+		if len(events) == 1 && (events[0].Event != EventPlaybackEnding || events[0].RemainingMs != 1500) {
+			t.Errorf("unexpected event: %+v", events[0])
+		}
+	}
+}
+
+func TestWebhookSecretRedacted(t *testing.T) {
+	_, r := setupTest(t)
+	webhooks.replace(nil)
+	defer webhooks.replace(nil)
+
+	w := doRequest(r, http.MethodPost, "/api/webhooks", `{"url":"http://example.com/hook","events":["input_live"],"secret":"s3cret"}`)
+	if w.Code != http.StatusCreated || strings.Contains(w.Body.String(), "s3cret") || !strings.Contains(w.Body.String(), `"has_secret":true`) {
+		t.Fatalf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+	hook := WebhookResponse{}
+	json.Unmarshal(w.Body.Bytes(), &hook)
+
+	if w := doRequest(r, http.MethodGet, "/api/webhooks", ""); strings.Contains(w.Body.String(), "s3cret") || !strings.Contains(w.Body.String(), `"has_secret":true`) {
+		t.Errorf("secret not redacted in list: %s", w.Body.String())
+	}
+
+	// Omitted secret keeps the current one.
+	w = doRequest(r, http.MethodPut, "/api/webhooks/"+hook.ID, `{"url":"http://example.com/other","events":["input_live"]}`)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "s3cret") || !strings.Contains(w.Body.String(), `"has_secret":true`) {
+		t.Errorf("unexpected update response: %d %s", w.Code, w.Body.String())
+	}
+	if hooks := webhooks.list(); len(hooks) != 1 || hooks[0].Secret != "s3cret" || hooks[0].URL != "http://example.com/other" {
+		t.Errorf("unexpected webhooks: %+v", hooks)
+	}
+
+	if w := doRequest(r, http.MethodGet, "/api/config/export", ""); !strings.Contains(w.Body.String(), `"secret":"s3cret"`) {
+		t.Errorf("secret not exported: %s", w.Body.String())
+	}
+}